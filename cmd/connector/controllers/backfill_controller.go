@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+var (
+	Backfill backfillController = &BackfillControllerImpl{}
+)
+
+type backfillController interface {
+	Run(uuid string, interval time.Duration, dryRun bool) (int, error)
+}
+
+type BackfillControllerImpl struct{}
+
+// Run checks the current patchset of every open change of the checker's repository that its query selects, through
+// the same ExecuteCheck path as pending checks. A check is dispatched every interval, which must be positive, and with
+// dryRun the changes are only listed. It returns the number of checks dispatched.
+func (controller *BackfillControllerImpl) Run(uuid string, interval time.Duration, dryRun bool) (int, error) {
+	checker, err := Checker.GetChecker(uuid)
+	if err != nil {
+		return 0, err
+	}
+	if checker.Status != services.CheckerEnabledString {
+		return 0, fmt.Errorf("checker %s is %s", uuid, checker.Status)
+	}
+
+	query := fmt.Sprintf("status:open project:%q", checker.Repository)
+	if checker.Query != "" {
+		query += " (" + checker.Query + ")"
+	}
+	changes, err := services.GerritChanges.QueryChanges(query, "CURRENT_REVISION")
+	if err != nil {
+		return 0, err
+	}
+	log.Printf("backfilling %s on %d changes", uuid, len(changes))
+
+	var tick <-chan time.Time
+	if !dryRun {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	dispatched := 0
+	for i, change := range changes {
+		pc := &types.PendingChecksInfo{
+			PatchSet: &types.CheckablePatchSetInfo{
+				Repository:   change.Project,
+				ChangeNumber: change.ChangeNumber,
+				PatchSetID:   change.Revisions[change.CurrentRevision].Number,
+			},
+			PendingChecks: map[string]*types.PendingCheckInfo{
+				uuid: {State: services.NotStartedString},
+			},
+		}
+		if dryRun {
+			log.Printf("would check %s", pc.PatchSet)
+			continue
+		}
+		if i > 0 {
+			<-tick
+		}
+		if err := services.GerritChecker.ExecuteCheck(pc); err != nil {
+			log.Printf("ExecuteCheck(%d): %v", change.ChangeNumber, err)
+			continue
+		}
+		dispatched++
+	}
+
+	return dispatched, nil
+}
@@ -0,0 +1,68 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/cmd/connector/controllers"
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestBackfillControllerImpl_Run(t *testing.T) {
+	// Arrange
+	uuid := services.EncodeCheckerUUID("lint", "MyProject")
+	var query string
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			body, err := json.Marshal(&types.CheckerInfo{UUID: uuid, Repository: "MyProject", Status: "ENABLED",
+				Query: "branch:master"})
+			return append([]byte(")]}'"), body...), err
+		},
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			query = u.Query().Get("q")
+			out := []*types.PendingSubmitInfo{
+				{Project: "MyProject", ChangeNumber: 1, CurrentRevision: "a",
+					Revisions: map[string]types.Revision{"a": {Number: 2}}},
+				{Project: "MyProject", ChangeNumber: 3, CurrentRevision: "b",
+					Revisions: map[string]types.Revision{"b": {Number: 1}}},
+			}
+			body, err := json.Marshal(&out)
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+	var checked []*types.PendingChecksInfo
+	services.GerritChecker = checkerServiceMock{
+		executeCheckFn: func(pc *types.PendingChecksInfo) error {
+			checked = append(checked, pc)
+			return nil
+		},
+	}
+
+	// Act
+	listed, err := controllers.Backfill.Run(uuid, 0, true)
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	dispatched, err := controllers.Backfill.Run(uuid, time.Millisecond, false)
+
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	if query != `status:open project:"MyProject" (branch:master)` {
+		t.Errorf("unexpected query: %q", query)
+	}
+	if listed != 0 || dispatched != 2 || len(checked) != 2 {
+		t.Fatalf("expected only the second run to dispatch 2 checks, got: %d, %d", listed, dispatched)
+	}
+	if checked[0].PatchSet.ChangeNumber != 1 || checked[0].PatchSet.PatchSetID != 2 ||
+		checked[0].PendingChecks[uuid] == nil {
+		t.Errorf("expected the current patchset to be checked, got: %s", checked[0].PatchSet)
+	}
+}
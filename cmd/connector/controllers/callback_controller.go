@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+var (
+	Callback callbackController = &CallbackControllerImpl{}
+)
+
+type callbackController interface {
+	Handler() http.Handler
+	ListenAndServe(addr string) error
+}
+
+type CallbackControllerImpl struct {
+	// Token is the bearer token requests to the callback endpoints must carry. Without a token every callback is
+	// refused.
+	Token string
+}
+
+// Handler returns the HTTP handler serving the endpoints pipelines report their results to, which require the
+// bearer token, and the read-only status endpoints, which do not.
+func (controller *CallbackControllerImpl) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/callbacks/merge", controller.authenticate(http.HandlerFunc(controller.handleMerge)))
+	mux.Handle("/callbacks/post-merge", controller.authenticate(http.HandlerFunc(controller.handlePostMerge)))
+	mux.Handle("/callbacks/periodic", controller.authenticate(http.HandlerFunc(controller.handlePeriodic)))
+	mux.HandleFunc("/periodic", controller.handlePeriodicStatus)
+	mux.HandleFunc("/explain", controller.handleExplain)
+	mux.HandleFunc("/metrics", controller.handleMetrics)
+	return mux
+}
+
+// authenticate only passes on the requests carrying the controller's bearer token.
+func (controller *CallbackControllerImpl) authenticate(next http.Handler) http.Handler {
+	expected := []byte("Bearer " + controller.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual := []byte(r.Header.Get("Authorization"))
+		if controller.Token == "" || subtle.ConstantTimeCompare(actual, expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ListenAndServe serves the callback endpoints on the given address. It should be executed in a goroutine.
+func (controller *CallbackControllerImpl) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, controller.Handler())
+}
+
+// handleMerge receives the result of a merge pipeline.
+func (controller *CallbackControllerImpl) handleMerge(w http.ResponseWriter, r *http.Request) {
+	result := types.PipelineResult{}
+	if !decodeCallback(w, r, &result) {
+		return
+	}
+
+	if err := services.GerritSubmitter.ReportMergeResult(&result); err != nil {
+		log.Printf("ReportMergeResult(%+v): %v", result, err)
+		code := http.StatusInternalServerError
+		if errors.Is(err, services.ErrNotLocked) {
+			code = http.StatusConflict
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePostMerge receives the result of a post-merge pipeline.
+func (controller *CallbackControllerImpl) handlePostMerge(w http.ResponseWriter, r *http.Request) {
+	result := types.PipelineResult{}
+	if !decodeCallback(w, r, &result) {
+		return
+	}
+
+	if err := services.GerritPostMerge.ReportPostMergeResult(&result); err != nil {
+		log.Printf("ReportPostMergeResult(%+v): %v", result, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePeriodic receives the result of a periodic pipeline.
+func (controller *CallbackControllerImpl) handlePeriodic(w http.ResponseWriter, r *http.Request) {
+	result := types.PeriodicResult{}
+	if !decodeCallback(w, r, &result) {
+		return
+	}
+
+	if err := services.Periodic.ReportPeriodicResult(&result); err != nil {
+		log.Printf("ReportPeriodicResult(%+v): %v", result, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePeriodicStatus renders the latest run of every periodic job as a plain text table.
+func (controller *CallbackControllerImpl) handlePeriodicStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tPROJECT\tBRANCH\tREVISION\tSTATUS\tSTARTED\tFINISHED\tMESSAGE\tURL")
+	for _, run := range services.Periodic.PeriodicRuns() {
+		finished := ""
+		if !time.Time(run.Finished).IsZero() {
+			finished = formatTime(run.Finished)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", run.Name, run.Project, run.Branch, run.Revision,
+			run.Status, formatTime(run.Started), finished, run.Message, run.URL)
+	}
+	if err := tw.Flush(); err != nil {
+		log.Printf("handlePeriodicStatus: %v", err)
+	}
+}
+
+// handleExplain renders how the change given by the "change" parameter fares against every submit condition, as
+// JSON or, with "output=table", as plain text.
+func (controller *CallbackControllerImpl) handleExplain(w http.ResponseWriter, r *http.Request) {
+	change := r.URL.Query().Get("change")
+	if change == "" {
+		http.Error(w, "missing change parameter", http.StatusBadRequest)
+		return
+	}
+	output := r.URL.Query().Get("output")
+	if output == "" {
+		output = OutputJSON
+	}
+	if output != OutputJSON && output != OutputTable {
+		http.Error(w, fmt.Sprintf("unknown output format %q", output), http.StatusBadRequest)
+		return
+	}
+
+	var out bytes.Buffer
+	if err := Explain.Explain(&out, change, output); err != nil {
+		log.Printf("Explain(%s): %v", change, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if output == OutputJSON {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	if _, err := out.WriteTo(w); err != nil {
+		log.Printf("handleExplain: %v", err)
+	}
+}
+
+// handleMetrics renders the connector's metrics in the Prometheus text format.
+func (controller *CallbackControllerImpl) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := Drift.WriteMetrics(w); err != nil {
+		log.Printf("handleMetrics: %v", err)
+	}
+}
+
+// formatTime formats a timestamp for display.
+func formatTime(ts types.Timestamp) string {
+	return time.Time(ts).Format(time.RFC3339)
+}
+
+// decodeCallback decodes the JSON body of a callback request, replying with an error if it is not valid.
+func decodeCallback(w http.ResponseWriter, r *http.Request, dest interface{}) bool {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,250 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/cmd/connector/controllers"
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+const callbackToken = "secret"
+
+// callbackRequest returns a request to the callback endpoints carrying the callback token.
+func callbackRequest(method, target, body string) *http.Request {
+	controllers.Callback = &controllers.CallbackControllerImpl{Token: callbackToken}
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+callbackToken)
+	return req
+}
+
+func TestCallbackControllerImpl_HandleMerge(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Submit.Mode = services.SubmitModeConnector
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	var submitted string
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			return []byte(`)]}'{"_number": 10, "current_revision": "abc", "revisions": {"abc": {"_number": 2}},
+				"labels": {"Jarvis-Lock": {"approved": {"_account_id": 1}}}}`), nil
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			submitted = pathing
+			return []byte(")]}'{}"), nil
+		},
+	}
+	body := `{"changeNumber": "10", "patchSetNumber": "2", "status": "SUCCESS"}`
+	req := callbackRequest(http.MethodPost, "/callbacks/merge", body)
+	rec := httptest.NewRecorder()
+
+	// Act
+	controllers.Callback.Handler().ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got: %d %s", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+	if submitted != "a/changes/10/revisions/2/submit" {
+		t.Errorf("expected the locked revision to be submitted, got: %q", submitted)
+	}
+}
+
+func TestCallbackControllerImpl_HandleMergeNotLocked(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Submit.Mode = services.SubmitModeConnector
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	var submitted []string
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			return []byte(`)]}'{"_number": 10, "current_revision": "abc", "revisions": {"abc": {"_number": 2}}}`), nil
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			submitted = append(submitted, pathing)
+			return []byte(")]}'{}"), nil
+		},
+	}
+	body := `{"changeNumber": "10", "patchSetNumber": "2", "status": "SUCCESS"}`
+	req := callbackRequest(http.MethodPost, "/callbacks/merge", body)
+	rec := httptest.NewRecorder()
+
+	// Act
+	controllers.Callback.Handler().ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected status %d, got: %d %s", http.StatusConflict, rec.Code, rec.Body.String())
+	}
+	if len(submitted) > 0 {
+		t.Errorf("expected a change without the Jarvis-Lock to be left alone, got: %v", submitted)
+	}
+}
+
+func TestCallbackControllerImpl_Unauthorized(t *testing.T) {
+	testData := []struct {
+		name          string
+		token         string
+		authorization string
+	}{
+		{name: "missing", token: callbackToken},
+		{name: "wrong", token: callbackToken, authorization: "Bearer guess"},
+		{name: "no token configured", authorization: "Bearer "},
+	}
+
+	for _, test := range testData {
+		// Arrange
+		var posted []string
+		services.GerritServer = serverServiceMock{
+			postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+				posted = append(posted, pathing)
+				return []byte(")]}'{}"), nil
+			},
+		}
+		controllers.Callback = &controllers.CallbackControllerImpl{Token: test.token}
+		body := `{"changeNumber": "10", "patchSetNumber": "2", "status": "FAILURE"}`
+		req := httptest.NewRequest(http.MethodPost, "/callbacks/merge", strings.NewReader(body))
+		if test.authorization != "" {
+			req.Header.Set("Authorization", test.authorization)
+		}
+		rec := httptest.NewRecorder()
+
+		// Act
+		controllers.Callback.Handler().ServeHTTP(rec, req)
+
+		// Assert
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: expected status %d, got: %d", test.name, http.StatusUnauthorized, rec.Code)
+		}
+		if len(posted) > 0 {
+			t.Errorf("%s: expected no requests to gerrit, got: %v", test.name, posted)
+		}
+	}
+}
+
+func TestCallbackControllerImpl_ReadOnlyWithoutToken(t *testing.T) {
+	// Arrange
+	controllers.Callback = &controllers.CallbackControllerImpl{Token: callbackToken}
+
+	for _, target := range []string{"/periodic", "/metrics"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		controllers.Callback.Handler().ServeHTTP(rec, req)
+
+		// Assert
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected status %d without a token, got: %d", target, http.StatusOK, rec.Code)
+		}
+	}
+}
+
+func TestCallbackControllerImpl_HandleMergeInvalidBody(t *testing.T) {
+	// Arrange
+	req := callbackRequest(http.MethodPost, "/callbacks/merge", "not json")
+	rec := httptest.NewRecorder()
+
+	// Act
+	controllers.Callback.Handler().ServeHTTP(rec, req)
+
+	// Assert
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got: %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestCallbackControllerImpl_HandlePeriodicStatus(t *testing.T) {
+	// Arrange
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			return []byte(`)]}'{"ref": "refs/heads/master", "revision": "tip"}`), nil
+		},
+		getRepoRootFn: func() string {
+			return "https://website.com/"
+		},
+	}
+	services.EventListenerServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			return []byte{}, nil
+		},
+	}
+	job := &types.PeriodicJob{Name: "nightly", Repository: "MyProject", Branch: "master"}
+	if err := services.Periodic.ExecutePeriodic(job); err != nil {
+		t.Fatalf("Received error setting up TestCallbackControllerImpl_HandlePeriodicStatus function: %v", err)
+	}
+	body := `{"name": "nightly", "revision": "tip", "status": "SUCCESS", "url": "https://tekton.local/run/1"}`
+	report := callbackRequest(http.MethodPost, "/callbacks/periodic", body)
+	reportRec := httptest.NewRecorder()
+	status := httptest.NewRequest(http.MethodGet, "/periodic", nil)
+	statusRec := httptest.NewRecorder()
+
+	// Act
+	controllers.Callback.Handler().ServeHTTP(reportRec, report)
+	controllers.Callback.Handler().ServeHTTP(statusRec, status)
+
+	// Assert
+	if reportRec.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got: %d %s", http.StatusNoContent, reportRec.Code, reportRec.Body.String())
+	}
+	page := statusRec.Body.String()
+	if !strings.Contains(page, "nightly") || !strings.Contains(page, "SUCCESS") ||
+		!strings.Contains(page, "https://tekton.local/run/1") {
+		t.Errorf("status page is missing the run:\n%s", page)
+	}
+}
+
+func TestCallbackControllerImpl_HandleExplain(t *testing.T) {
+	// Arrange
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			if strings.HasSuffix(u.Path, "/related") {
+				return []byte(`)]}'{"changes": []}`), nil
+			}
+			if u.Query().Get("q") != "" {
+				return []byte(`)]}'[]`), nil
+			}
+			return []byte(`)]}'{"_number": 10, "status": "NEW", "mergeable": true, "submittable": true}`), nil
+		},
+	}
+	controllers.Callback = &controllers.CallbackControllerImpl{}
+	req := httptest.NewRequest(http.MethodGet, "/explain?change=10&output=table", nil)
+	rec := httptest.NewRecorder()
+	missing := httptest.NewRequest(http.MethodGet, "/explain", nil)
+	missingRec := httptest.NewRecorder()
+
+	// Act
+	controllers.Callback.Handler().ServeHTTP(rec, req)
+	controllers.Callback.Handler().ServeHTTP(missingRec, missing)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got: %d %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	page := rec.Body.String()
+	if !strings.Contains(page, "change 10") || !strings.Contains(page, "will not be submitted") ||
+		!strings.Contains(page, "\nquery ") || strings.Fields(page[strings.Index(page, "\nquery "):])[1] != "FAIL" {
+		t.Errorf("expected the change to fail the submit query, got:\n%s", page)
+	}
+	if missingRec.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d without a change, got: %d", http.StatusBadRequest, missingRec.Code)
+	}
+}
@@ -1,11 +1,15 @@
 package controllers
 
 import (
-	"crypto/sha1" //nolint
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/att-comdev/jarvis-connector/services"
 	"github.com/att-comdev/jarvis-connector/types"
@@ -13,6 +17,12 @@ import (
 
 const (
 	checkerScheme = "jarvis"
+
+	defaultCheckerDescription = "check source code formatting."
+	defaultCheckerQuery       = "status:open"
+
+	// archivedPrefix marks the description of archived checkers.
+	archivedPrefix = "Archived: "
 )
 
 var (
@@ -20,43 +30,323 @@ var (
 )
 
 type checkerController interface {
-	PostChecker(repo, prefix string, update bool) (*types.CheckerInfo, error)
-	ListCheckers() error
+	PostChecker(spec types.CheckerSpec, update bool) (*types.CheckerInfo, error)
+	RegisterAll(spec types.CheckerSpec, projectRegex, parent string, dryRun bool) (*types.RegistrationSummary, error)
+	ListCheckers(filter types.CheckerFilter) ([]*types.CheckerInfo, error)
+	PrintCheckers(w io.Writer, checkers []*types.CheckerInfo, output string) error
+	GetChecker(uuid string) (*types.CheckerInfo, error)
+	SetCheckerStatus(uuid, status string) (*types.CheckerInfo, error)
+	ArchiveChecker(uuid string) (*types.CheckerInfo, error)
+	PlanCheckers(specs []types.CheckerSpec) ([]*types.CheckerAction, error)
+	PlanMigration() ([]*types.CheckerAction, error)
+	ApplyCheckers(plan []*types.CheckerAction) (int, error)
+	DeleteChecker(uuid string) error
 }
 
 type CheckerControllerImpl struct{}
 
-// PostChecker creates or changes a checker. It sets up a checker on the given repo, for the given prefix.
-func (controller *CheckerControllerImpl) PostChecker(repo, prefix string, update bool) (*types.CheckerInfo, error) {
-	hash := sha1.New() //nolint
-	hash.Write([]byte(repo)) //nolint
+// PostChecker creates or changes a checker. It sets up a checker on the spec's repo, for the spec's prefix. Unset
+// fields take their defaults on a new checker, and keep their current value on an updated one.
+func (controller *CheckerControllerImpl) PostChecker(spec types.CheckerSpec, update bool) (*types.CheckerInfo, error) {
+	if err := services.ValidateCheckerSpec(spec); err != nil {
+		return nil, err
+	}
+
+	in := specCheckerInput(spec)
+	if update {
+		info, err := controller.GetChecker(in.UUID)
+		if err != nil {
+			return nil, err
+		}
+		in = updateCheckerInput(info, spec)
+	}
+	return postCheckerInput(&in, update)
+}
+
+// RegisterAll creates or updates the spec's checker on every project whose name matches projectRegex, which is
+// anchored, and whose parent is parent. Empty filters match every project. Unset fields of the spec keep their current
+// value on existing checkers, checkers that are already up to date are skipped, and with dryRun nothing is changed.
+func (controller *CheckerControllerImpl) RegisterAll(
+	spec types.CheckerSpec, projectRegex, parent string, dryRun bool) (*types.RegistrationSummary, error) {
+	var re *regexp.Regexp
+	if projectRegex != "" {
+		var err error
+		if re, err = regexp.Compile("^(?:" + projectRegex + ")$"); err != nil {
+			return nil, err
+		}
+	}
+	projects, err := services.GerritChanges.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	current, err := jarvisCheckers()
+	if err != nil {
+		return nil, err
+	}
+	registered := map[string]*types.CheckerInfo{}
+	for _, info := range current {
+		registered[info.UUID] = info
+	}
+
+	summary := &types.RegistrationSummary{}
+	for _, project := range projects {
+		if (re != nil && !re.MatchString(project.Name)) || (parent != "" && project.Parent != parent) {
+			continue
+		}
+		spec.Repository = project.Name
+		if err := services.ValidateCheckerSpec(spec); err != nil {
+			return nil, err
+		}
+		in := specCheckerInput(spec)
+		info, update := registered[in.UUID]
+		if update {
+			if in = updateCheckerInput(info, spec); len(checkerChanges(info, &in)) == 0 {
+				summary.Skipped = append(summary.Skipped, project.Name)
+				continue
+			}
+		}
+		if !dryRun {
+			if _, err := postCheckerInput(&in, update); err != nil {
+				log.Printf("registering %s on %s: %v", in.UUID, project.Name, err)
+				summary.Failed = append(summary.Failed, project.Name)
+				continue
+			}
+		}
+		if update {
+			summary.Updated = append(summary.Updated, project.Name)
+		} else {
+			summary.Created = append(summary.Created, project.Name)
+		}
+	}
+
+	return summary, nil
+}
+
+// ListCheckers returns the checkers for our scheme that match the filter. The status is matched case-insensitively.
+func (controller *CheckerControllerImpl) ListCheckers(filter types.CheckerFilter) ([]*types.CheckerInfo, error) {
+	checkers, err := jarvisCheckers()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := checkers[:0]
+	for _, checker := range checkers {
+		if filter.Repository != "" && checker.Repository != filter.Repository {
+			continue
+		}
+		if filter.Status != "" && !strings.EqualFold(checker.Status, filter.Status) {
+			continue
+		}
+		if filter.Prefix != "" {
+			if prefix, _, _ := services.DecodeCheckerUUID(checker.UUID); prefix != filter.Prefix {
+				continue
+			}
+		}
+		filtered = append(filtered, checker)
+	}
+
+	return filtered, nil
+}
+
+// PrintCheckers writes checkers as a table, as JSON or as YAML.
+func (controller *CheckerControllerImpl) PrintCheckers(
+	w io.Writer, checkers []*types.CheckerInfo, output string) error {
+	switch output {
+	case OutputTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "UUID\tNAME\tREPOSITORY\tSTATUS\tBLOCKING\tQUERY")
+		for _, c := range checkers {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", c.UUID, c.Name, c.Repository, c.Status,
+				strings.Join(c.Blocking, ","), c.Query)
+		}
+		return tw.Flush()
+	case OutputJSON:
+		if checkers == nil {
+			checkers = []*types.CheckerInfo{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(checkers)
+	case OutputYAML:
+		return writeCheckersYAML(w, checkers)
+	}
+	return fmt.Errorf("unknown output format %q", output)
+}
+
+// writeCheckersYAML writes checkers as a YAML sequence. Strings are double quoted, and escaped the way Go quotes
+// them, which YAML accepts.
+func writeCheckersYAML(w io.Writer, checkers []*types.CheckerInfo) error {
+	if len(checkers) == 0 {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+
+	var b strings.Builder
+	for _, c := range checkers {
+		fields := []struct {
+			key   string
+			value string
+		}{
+			{"uuid", c.UUID},
+			{"name", c.Name},
+			{"description", c.Description},
+			{"url", c.URL},
+			{"repository", c.Repository},
+			{"status", c.Status},
+			{"query", c.Query},
+			{"created", formatTime(c.Created)},
+			{"updated", formatTime(c.Updated)},
+		}
+		for i, field := range fields {
+			indent := "  "
+			if i == 0 {
+				indent = "- "
+			}
+			fmt.Fprintf(&b, "%s%s: %s\n", indent, field.key, strconv.Quote(field.value))
+		}
+		if len(c.Blocking) == 0 {
+			b.WriteString("  blocking: []\n")
+			continue
+		}
+		b.WriteString("  blocking:\n")
+		for _, condition := range c.Blocking {
+			fmt.Fprintf(&b, "    - %s\n", strconv.Quote(condition))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// GetChecker returns a single checker.
+func (controller *CheckerControllerImpl) GetChecker(uuid string) (*types.CheckerInfo, error) {
+	content, err := services.GerritServer.GetPath("a/plugins/checks/checkers/"+uuid, checkerHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	out := types.CheckerInfo{}
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// SetCheckerStatus enables or disables one of our checkers.
+func (controller *CheckerControllerImpl) SetCheckerStatus(uuid, status string) (*types.CheckerInfo, error) {
+	if status != services.CheckerEnabledString && status != services.CheckerDisabledString {
+		return nil, fmt.Errorf("unknown checker status %q", status)
+	}
+
+	return controller.modifyChecker(uuid, func(in *types.CheckerInput) {
+		in.Status = status
+	})
+}
+
+// ArchiveChecker disables one of our checkers and marks it as archived in its description. Unlike a deleted checker,
+// the checks it reported stay visible on the changes.
+func (controller *CheckerControllerImpl) ArchiveChecker(uuid string) (*types.CheckerInfo, error) {
+	return controller.modifyChecker(uuid, func(in *types.CheckerInput) {
+		in.Status = services.CheckerDisabledString
+		if !strings.HasPrefix(in.Description, archivedPrefix) {
+			in.Description = archivedPrefix + in.Description
+		}
+	})
+}
+
+// DeleteChecker deletes one of our checkers. Gerrit versions whose checks plugin cannot delete checkers refuse the
+// request, those checkers can only be archived.
+func (controller *CheckerControllerImpl) DeleteChecker(uuid string) error {
+	if !strings.HasPrefix(uuid, checkerScheme+":") {
+		return fmt.Errorf("checker %q is not a %s checker", uuid, checkerScheme)
+	}
+
+	_, err := services.GerritServer.DeletePath("a/plugins/checks/checkers/"+uuid, checkerHeaders())
+	if statusErr, ok := err.(*services.StatusError); ok &&
+		(statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusMethodNotAllowed) {
+		return fmt.Errorf("gerrit cannot delete checker %q (status %d), archive it instead", uuid, statusErr.StatusCode)
+	}
+	return err
+}
+
+// modifyChecker fetches one of our checkers, applies the modification and posts it back.
+func (controller *CheckerControllerImpl) modifyChecker(
+	uuid string, modify func(in *types.CheckerInput)) (*types.CheckerInfo, error) {
+	if !strings.HasPrefix(uuid, checkerScheme+":") {
+		return nil, fmt.Errorf("checker %q is not a %s checker", uuid, checkerScheme)
+	}
+
+	info, err := controller.GetChecker(uuid)
+	if err != nil {
+		return nil, err
+	}
+	in := infoCheckerInput(info)
+	modify(&in)
+
+	return postCheckerInput(&in, true)
+}
 
-	uuid := fmt.Sprintf("%s:%s-%x", checkerScheme, prefix, hash.Sum(nil))
+// specCheckerInput returns the checker input for a checker spec, filling unset fields with their defaults.
+func specCheckerInput(spec types.CheckerSpec) types.CheckerInput {
 	in := types.CheckerInput{
-		UUID:        uuid,
-		Name:        prefix,
-		Description: "check source code formatting.",
-		URL:         "",
-		Repository:  repo,
-		Status:      "ENABLED",
-		Blocking:    []string{},
-		Query:       "status:open",
+		UUID:        services.EncodeCheckerUUID(spec.Prefix, spec.Repository),
+		Name:        spec.Prefix,
+		Description: spec.Description,
+		URL:         spec.URL,
+		Repository:  spec.Repository,
+		Status:      spec.Status,
+		Blocking:    spec.Blocking,
+		Query:       spec.Query,
+	}
+	if in.Description == "" {
+		in.Description = defaultCheckerDescription
+	}
+	if in.Status == "" {
+		in.Status = services.CheckerEnabledString
+	}
+	if in.Blocking == nil {
+		in.Blocking = []string{}
 	}
+	if in.Query == "" {
+		in.Query = defaultCheckerQuery
+	}
+	return in
+}
 
-	body, err := json.Marshal(&in)
+// updateCheckerInput returns the checker input updating a registered checker with the fields set in a checker spec.
+// The other fields keep their registered value.
+func updateCheckerInput(info *types.CheckerInfo, spec types.CheckerSpec) types.CheckerInput {
+	in := infoCheckerInput(info)
+	if spec.Description != "" {
+		in.Description = spec.Description
+	}
+	if spec.URL != "" {
+		in.URL = spec.URL
+	}
+	if spec.Status != "" {
+		in.Status = spec.Status
+	}
+	if spec.Blocking != nil {
+		in.Blocking = spec.Blocking
+	}
+	if spec.Query != "" {
+		in.Query = spec.Query
+	}
+	return in
+}
+
+// postCheckerInput creates the checker, or updates the existing checker with the same UUID.
+func postCheckerInput(in *types.CheckerInput, update bool) (*types.CheckerInfo, error) {
+	body, err := json.Marshal(in)
 	if err != nil {
 		return nil, err
 	}
 
 	path := "a/plugins/checks/checkers/"
 	if update {
-		path += uuid
+		path += in.UUID
 	}
-	headers := []types.Header{{
-		Key:   "Content-Type",
-		Value: "application/json",
-	}}
-	content, err := services.GerritServer.PostPath(path, headers, body)
+	content, err := services.GerritServer.PostPath(path, checkerHeaders(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -69,20 +359,16 @@ func (controller *CheckerControllerImpl) PostChecker(repo, prefix string, update
 	return &out, nil
 }
 
-// ListCheckers returns all the checkers for our scheme.
-func (controller *CheckerControllerImpl) ListCheckers() error {
-	headers := []types.Header{{
-		Key:   "Content-Type",
-		Value: "application/json",
-	}}
-	c, err := services.GerritServer.GetPath("a/plugins/checks/checkers/", headers)
+// jarvisCheckers returns all the checkers for our scheme.
+func jarvisCheckers() ([]*types.CheckerInfo, error) {
+	c, err := services.GerritServer.GetPath("a/plugins/checks/checkers/", checkerHeaders())
 	if err != nil {
-		log.Fatalf("ListCheckers: %v", err)
+		return nil, err
 	}
 
 	var out []*types.CheckerInfo
 	if err := types.Unmarshal(c, &out); err != nil {
-		return err
+		return nil, err
 	}
 
 	filtered := out[:0]
@@ -96,14 +382,12 @@ func (controller *CheckerControllerImpl) ListCheckers() error {
 
 		filtered = append(filtered, o)
 	}
+	return filtered, nil
+}
 
-	for _, obj := range filtered {
-		marshalledJSON, err := json.Marshal(obj)
-		if err != nil {
-			log.Printf("error marshaling CheckerInfo: %v", err)
-		}
-		log.Printf("%v \n", string(marshalledJSON))
-	}
-
-	return nil
+func checkerHeaders() []types.Header {
+	return []types.Header{{
+		Key:   "Content-Type",
+		Value: "application/json",
+	}}
 }
@@ -1,7 +1,11 @@
 package controllers_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -52,7 +56,7 @@ func TestCheckerControllerImpl_PostChecker(t *testing.T) {
 	services.GerritServer = serverMock
 
 	// Act
-	actual, err := controllers.Checker.PostChecker(repo, prefix, false)
+	actual, err := controllers.Checker.PostChecker(types.CheckerSpec{Repository: repo, Prefix: prefix}, false)
 
 	// Assert
 	if err != nil {
@@ -101,10 +105,359 @@ func TestCheckerControllerImpl_ListCheckers(t *testing.T) {
 	services.GerritChecker = checkerMock
 
 	// Act
-	err := controllers.Checker.ListCheckers()
+	checkers, err := controllers.Checker.ListCheckers(types.CheckerFilter{})
 
 	// Assert
 	if err != nil {
 		t.Errorf("resulting error expected to be nil, received: %v", err)
 	}
+	if len(checkers) != 1 || checkers[0].UUID != "jarvis:UUID" {
+		t.Errorf("expected the jarvis checker to be returned, got: %v", checkers)
+	}
+}
+
+func TestCheckerControllerImpl_ListCheckersFilter(t *testing.T) {
+	// Arrange
+	registered := []*types.CheckerInfo{
+		{UUID: services.EncodeCheckerUUID("lint", "a"), Name: "lint", Repository: "a", Status: "ENABLED"},
+		{UUID: services.EncodeCheckerUUID("unit", "a"), Name: "unit", Repository: "a", Status: "DISABLED"},
+		{UUID: services.EncodeCheckerUUID("lint", "b"), Name: "lint", Repository: "b", Status: "ENABLED",
+			Blocking: []string{"STATE_NOT_PASSING"}, Description: `say "hi"`},
+	}
+	services.GerritChecker = &services.GerritCheckerServiceImpl{}
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			body, err := json.Marshal(&registered)
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+	testData := []struct {
+		filter   types.CheckerFilter
+		expected int
+	}{
+		{filter: types.CheckerFilter{}, expected: 3},
+		{filter: types.CheckerFilter{Repository: "a"}, expected: 2},
+		{filter: types.CheckerFilter{Status: "ENABLED"}, expected: 2},
+		{filter: types.CheckerFilter{Status: "disabled"}, expected: 1},
+		{filter: types.CheckerFilter{Prefix: "lint", Repository: "b"}, expected: 1},
+	}
+
+	for _, test := range testData {
+		// Act
+		checkers, err := controllers.Checker.ListCheckers(test.filter)
+
+		// Assert
+		if err != nil {
+			t.Errorf("%+v: resulting error expected to be nil, received: %v", test.filter, err)
+		}
+		if len(checkers) != test.expected {
+			t.Errorf("%+v: expected %d checkers, got: %d", test.filter, test.expected, len(checkers))
+		}
+	}
+}
+
+func TestCheckerControllerImpl_PrintCheckers(t *testing.T) {
+	// Arrange
+	checkers := []*types.CheckerInfo{
+		{UUID: "jarvis:lint.me", Name: "lint", Repository: "b", Status: "ENABLED",
+			Blocking: []string{"STATE_NOT_PASSING"}, Description: `say "hi"`},
+		{UUID: "jarvis:unit.me", Name: "unit", Repository: "b", Status: "DISABLED"},
+	}
+	var table, out, yaml, empty bytes.Buffer
+
+	// Act
+	tableErr := controllers.Checker.PrintCheckers(&table, checkers, controllers.OutputTable)
+	jsonErr := controllers.Checker.PrintCheckers(&out, checkers, controllers.OutputJSON)
+	yamlErr := controllers.Checker.PrintCheckers(&yaml, checkers, controllers.OutputYAML)
+	emptyErr := controllers.Checker.PrintCheckers(&empty, nil, controllers.OutputYAML)
+
+	// Assert
+	if tableErr != nil || jsonErr != nil || yamlErr != nil || emptyErr != nil {
+		t.Fatalf("resulting errors expected to be nil, received: %v, %v, %v, %v", tableErr, jsonErr, yamlErr, emptyErr)
+	}
+	if lines := strings.Split(strings.TrimSpace(table.String()), "\n"); len(lines) != 3 ||
+		!strings.HasPrefix(lines[0], "UUID") {
+		t.Errorf("expected a header and 2 rows, got:\n%s", table.String())
+	}
+	var decoded []*types.CheckerInfo
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded) != 2 {
+		t.Errorf("expected the checkers as JSON, got: %s (%v)", out.String(), err)
+	}
+	for _, expected := range []string{"- uuid: \"jarvis:lint.me\"\n", "  description: \"say \\\"hi\\\"\"\n",
+		"  blocking:\n    - \"STATE_NOT_PASSING\"\n", "  blocking: []\n"} {
+		if !strings.Contains(yaml.String(), expected) {
+			t.Errorf("expected %q in YAML:\n%s", expected, yaml.String())
+		}
+	}
+	if empty.String() != "[]\n" {
+		t.Errorf("expected an empty YAML sequence, got: %q", empty.String())
+	}
+}
+
+func TestCheckerControllerImpl_SetCheckerStatus(t *testing.T) {
+	// Arrange
+	uuid := "jarvis:myprefix-0123456789abcdef0123456789abcdef01234567"
+	var posted types.CheckerInput
+	var postedPath string
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			out := types.CheckerInfo{UUID: uuid, Name: "myprefix", Repository: "myrepo", Status: "ENABLED",
+				Description: "check source code formatting.", Query: "status:open"}
+			body, err := json.Marshal(&out)
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			postedPath = pathing
+			if err := json.Unmarshal(content, &posted); err != nil {
+				return nil, err
+			}
+			body, err := json.Marshal(&types.CheckerInfo{UUID: posted.UUID, Status: posted.Status})
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+
+	// Act
+	actual, err := controllers.Checker.SetCheckerStatus(uuid, services.CheckerDisabledString)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	if postedPath != "a/plugins/checks/checkers/"+uuid {
+		t.Errorf("expected the checker to be updated, got path: %q", postedPath)
+	}
+	if posted.Status != "DISABLED" || posted.Repository != "myrepo" || posted.Query != "status:open" {
+		t.Errorf("expected only the status to change, got: %+v", posted)
+	}
+	if actual.Status != "DISABLED" {
+		t.Errorf("expected the updated checker to be returned, got: %+v", actual)
+	}
+	if _, err := controllers.Checker.SetCheckerStatus("other:checker", "DISABLED"); err == nil {
+		t.Errorf("expected checkers of other schemes to be refused")
+	}
+}
+
+func TestCheckerControllerImpl_ArchiveChecker(t *testing.T) {
+	// Arrange
+	uuid := "jarvis:myprefix-0123456789abcdef0123456789abcdef01234567"
+	var posted types.CheckerInput
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			out := types.CheckerInfo{UUID: uuid, Status: "ENABLED", Description: "check source code formatting."}
+			body, err := json.Marshal(&out)
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			return []byte(")]}'{}"), json.Unmarshal(content, &posted)
+		},
+	}
+
+	// Act
+	_, err := controllers.Checker.ArchiveChecker(uuid)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	if posted.Status != "DISABLED" || posted.Description != "Archived: check source code formatting." {
+		t.Errorf("expected the checker to be disabled and marked archived, got: %+v", posted)
+	}
+}
+
+func TestCheckerControllerImpl_DeleteChecker(t *testing.T) {
+	// Arrange
+	uuid := "jarvis:myprefix-0123456789abcdef0123456789abcdef01234567"
+	var deleted string
+	services.GerritServer = serverServiceMock{
+		deletePathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			deleted = pathing
+			return nil, &services.StatusError{Method: "DeletePath", URL: pathing, StatusCode: http.StatusMethodNotAllowed}
+		},
+	}
+
+	// Act
+	err := controllers.Checker.DeleteChecker(uuid)
+
+	// Assert
+	if deleted != "a/plugins/checks/checkers/"+uuid {
+		t.Errorf("expected the checker to be deleted, got path: %q", deleted)
+	}
+	if err == nil || !strings.Contains(err.Error(), "archive") {
+		t.Errorf("expected an error suggesting to archive the checker, got: %v", err)
+	}
+}
+
+func TestCheckerControllerImpl_PostCheckerFields(t *testing.T) {
+	testData := []struct {
+		name  string
+		spec  types.CheckerSpec
+		valid bool
+	}{
+		{name: "all fields", spec: types.CheckerSpec{Description: "lint", URL: "https://tekton.local/lint",
+			Blocking: []string{"STATE_NOT_PASSING"}, Query: "status:open branch:master", Status: "DISABLED"},
+			valid: true},
+		{name: "unknown blocking condition", spec: types.CheckerSpec{Blocking: []string{"ALWAYS"}}},
+		{name: "relative url", spec: types.CheckerSpec{URL: "tekton.local/lint"}},
+		{name: "unknown status", spec: types.CheckerSpec{Status: "ON"}},
+	}
+
+	for _, test := range testData {
+		// Arrange
+		var posted *types.CheckerInput
+		services.GerritServer = serverServiceMock{
+			postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+				posted = &types.CheckerInput{}
+				return []byte(")]}'{}"), json.Unmarshal(content, posted)
+			},
+		}
+		test.spec.Repository = "myrepo"
+		test.spec.Prefix = "lint"
+
+		// Act
+		_, err := controllers.Checker.PostChecker(test.spec, false)
+
+		// Assert
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid: %t, got error: %v", test.name, test.valid, err)
+		}
+		if !test.valid {
+			if posted != nil {
+				t.Errorf("%s: expected no request for an invalid checker", test.name)
+			}
+			continue
+		}
+		if posted == nil || posted.Description != test.spec.Description || posted.URL != test.spec.URL ||
+			posted.Query != test.spec.Query || posted.Status != test.spec.Status ||
+			strings.Join(posted.Blocking, ",") != "STATE_NOT_PASSING" {
+			t.Errorf("%s: expected the configured fields to be sent, got: %+v", test.name, posted)
+		}
+	}
+}
+
+func TestCheckerControllerImpl_PostCheckerUpdate(t *testing.T) {
+	// Arrange
+	uuid := services.EncodeCheckerUUID("lint", "myrepo")
+	var fetched string
+	var posted []string
+	var updated types.CheckerInput
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			fetched = pathing
+			body, err := json.Marshal(&types.CheckerInfo{UUID: uuid, Name: "lint", Repository: "myrepo",
+				Description: "lint the code", URL: "https://tekton.local/lint", Status: "DISABLED",
+				Blocking: []string{"STATE_NOT_PASSING"}, Query: "status:open branch:master"})
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			posted = append(posted, pathing)
+			return []byte(")]}'{}"), json.Unmarshal(content, &updated)
+		},
+	}
+
+	// Act
+	_, err := controllers.Checker.PostChecker(types.CheckerSpec{Repository: "myrepo", Prefix: "lint",
+		Query: "status:open"}, true)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	if fetched != "a/plugins/checks/checkers/"+uuid {
+		t.Errorf("expected the registered checker to be fetched, got: %q", fetched)
+	}
+	if len(posted) != 1 || posted[0] != "a/plugins/checks/checkers/"+uuid {
+		t.Fatalf("expected the checker to be updated, got requests: %v", posted)
+	}
+	if updated.Query != "status:open" || updated.Description != "lint the code" ||
+		updated.URL != "https://tekton.local/lint" || updated.Status != "DISABLED" ||
+		strings.Join(updated.Blocking, ",") != "STATE_NOT_PASSING" {
+		t.Errorf("expected only the set fields to be updated, got: %+v", updated)
+	}
+}
+
+func TestCheckerControllerImpl_RegisterAll(t *testing.T) {
+	// Arrange
+	spec := types.CheckerSpec{Prefix: "lint", Query: "status:open"}
+	registered := []*types.CheckerInfo{
+		{UUID: services.EncodeCheckerUUID("lint", "airship/armada"), Name: "lint", Repository: "airship/armada",
+			Description: "old", Status: "ENABLED", Query: "status:open"},
+		{UUID: services.EncodeCheckerUUID("lint", "airship/shipyard"), Name: "lint", Repository: "airship/shipyard",
+			Description: "old", Status: "ENABLED", Query: "status:open branch:master"},
+	}
+	var query string
+	var posted []string
+	var updated types.CheckerInput
+	services.GerritChecker = &services.GerritCheckerServiceImpl{}
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			query = u.RawQuery
+			return []byte(`)]}'{
+				"airship/armada": {"id": "airship%2Farmada", "parent": "airship"},
+				"airship/deckhand": {"id": "airship%2Fdeckhand", "parent": "airship"},
+				"airship/shipyard": {"id": "airship%2Fshipyard", "parent": "airship"},
+				"airship/other": {"id": "airship%2Fother", "parent": "All-Projects"},
+				"openstack/nova": {"id": "openstack%2Fnova", "parent": "airship"}
+			}`), nil
+		},
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			body, err := json.Marshal(&registered)
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			posted = append(posted, pathing)
+			if pathing != "a/plugins/checks/checkers/" {
+				if err := json.Unmarshal(content, &updated); err != nil {
+					return nil, err
+				}
+			}
+			return []byte(")]}'{}"), nil
+		},
+	}
+
+	// Act
+	dryRun, err := controllers.Checker.RegisterAll(spec, "airship/.*", "airship", true)
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	dryPosted := len(posted)
+	parentOnly, err := controllers.Checker.RegisterAll(spec, "", "airship", true)
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	summary, err := controllers.Checker.RegisterAll(spec, "airship/.*", "airship", false)
+
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	if query != "t" {
+		t.Errorf("expected projects to be listed with their parent, got query: %q", query)
+	}
+	if dryPosted != 0 {
+		t.Errorf("expected a dry run not to change checkers, got %d requests", dryPosted)
+	}
+	if strings.Join(parentOnly.Created, ",") != "airship/deckhand,openstack/nova" ||
+		strings.Join(parentOnly.Updated, ",") != "airship/shipyard" ||
+		strings.Join(parentOnly.Skipped, ",") != "airship/armada" {
+		t.Errorf("expected every project of the parent without a project regex, got: %+v", parentOnly)
+	}
+	for _, s := range []*types.RegistrationSummary{dryRun, summary} {
+		if strings.Join(s.Created, ",") != "airship/deckhand" || strings.Join(s.Updated, ",") != "airship/shipyard" ||
+			strings.Join(s.Skipped, ",") != "airship/armada" || len(s.Failed) != 0 {
+			t.Errorf("unexpected summary: %+v", s)
+		}
+	}
+	expected := []string{
+		"a/plugins/checks/checkers/",
+		"a/plugins/checks/checkers/" + services.EncodeCheckerUUID("lint", "airship/shipyard"),
+	}
+	if strings.Join(posted, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected requests %v, got: %v", expected, posted)
+	}
+	if updated.Query != "status:open" || updated.Description != "old" {
+		t.Errorf("expected only the set fields to be updated, got: %+v", updated)
+	}
 }
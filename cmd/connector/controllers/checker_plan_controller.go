@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+const (
+	checkerActionCreate  = "create"
+	checkerActionUpdate  = "update"
+	checkerActionDisable = "disable"
+)
+
+// PlanCheckers compares the registered checkers of our scheme with the configured ones. The plan creates the
+// missing checkers, updates the ones that differ and disables the ones that are not configured.
+func (controller *CheckerControllerImpl) PlanCheckers(specs []types.CheckerSpec) ([]*types.CheckerAction, error) {
+	if err := services.ValidateCheckerSpecs(specs); err != nil {
+		return nil, err
+	}
+	current, err := jarvisCheckers()
+	if err != nil {
+		return nil, err
+	}
+	registered := map[string]*types.CheckerInfo{}
+	for _, info := range current {
+		registered[info.UUID] = info
+	}
+
+	var plan []*types.CheckerAction
+	wanted := map[string]bool{}
+	for _, spec := range specs {
+		in := specCheckerInput(spec)
+		wanted[in.UUID] = true
+		info, ok := registered[in.UUID]
+		if !ok {
+			plan = append(plan, &types.CheckerAction{Action: checkerActionCreate, Input: in})
+			continue
+		}
+		if changes := checkerChanges(info, &in); len(changes) > 0 {
+			plan = append(plan, &types.CheckerAction{Action: checkerActionUpdate, Input: in, Changes: changes})
+		}
+	}
+
+	for _, info := range current {
+		if wanted[info.UUID] || info.Status == services.CheckerDisabledString {
+			continue
+		}
+		in := infoCheckerInput(info)
+		in.Status = services.CheckerDisabledString
+		plan = append(plan, &types.CheckerAction{
+			Action:  checkerActionDisable,
+			Input:   in,
+			Changes: checkerChanges(info, &in),
+		})
+	}
+
+	return plan, nil
+}
+
+// PlanMigration plans re-registering the checkers with legacy UUIDs under their current UUID. The new checker
+// copies the legacy one, which is then disabled, as Gerrit cannot change the UUID of a checker. Checks reported
+// under the legacy UUID stay with the legacy checker.
+func (controller *CheckerControllerImpl) PlanMigration() ([]*types.CheckerAction, error) {
+	current, err := jarvisCheckers()
+	if err != nil {
+		return nil, err
+	}
+	registered := map[string]bool{}
+	for _, info := range current {
+		registered[info.UUID] = true
+	}
+
+	var plan []*types.CheckerAction
+	for _, info := range current {
+		if !services.IsLegacyCheckerUUID(info.UUID) {
+			continue
+		}
+		prefix, _, _ := services.DecodeCheckerUUID(info.UUID)
+		in := infoCheckerInput(info)
+		in.UUID = services.EncodeCheckerUUID(prefix, info.Repository)
+		if !registered[in.UUID] {
+			plan = append(plan, &types.CheckerAction{
+				Action:  checkerActionCreate,
+				Input:   in,
+				Changes: []string{fmt.Sprintf("uuid: %q -> %q", info.UUID, in.UUID)},
+			})
+		}
+		if info.Status != services.CheckerDisabledString {
+			old := infoCheckerInput(info)
+			old.Status = services.CheckerDisabledString
+			plan = append(plan, &types.CheckerAction{
+				Action:  checkerActionDisable,
+				Input:   old,
+				Changes: checkerChanges(info, &old),
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplyCheckers carries out a plan made by PlanCheckers or PlanMigration, stopping at the first action that fails.
+// It returns the number of actions carried out.
+func (controller *CheckerControllerImpl) ApplyCheckers(plan []*types.CheckerAction) (int, error) {
+	for i, action := range plan {
+		in := action.Input
+		if _, err := postCheckerInput(&in, action.Action != checkerActionCreate); err != nil {
+			return i, fmt.Errorf("%s %s: %v", action.Action, in.UUID, err)
+		}
+		log.Printf("%s %s: done", action.Action, in.UUID)
+	}
+	return len(plan), nil
+}
+
+// infoCheckerInput returns the checker input that keeps a registered checker as it is.
+func infoCheckerInput(info *types.CheckerInfo) types.CheckerInput {
+	in := types.CheckerInput{
+		UUID:        info.UUID,
+		Name:        info.Name,
+		Description: info.Description,
+		URL:         info.URL,
+		Repository:  info.Repository,
+		Status:      info.Status,
+		Blocking:    info.Blocking,
+		Query:       info.Query,
+	}
+	if in.Blocking == nil {
+		in.Blocking = []string{}
+	}
+	return in
+}
+
+// checkerChanges describes the fields the input changes on a registered checker.
+func checkerChanges(info *types.CheckerInfo, in *types.CheckerInput) []string {
+	var changes []string
+	diff := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s: %q -> %q", field, from, to))
+		}
+	}
+	diff("name", info.Name, in.Name)
+	diff("description", info.Description, in.Description)
+	diff("url", info.URL, in.URL)
+	diff("repository", info.Repository, in.Repository)
+	diff("status", info.Status, in.Status)
+	diff("blocking", strings.Join(info.Blocking, ","), strings.Join(in.Blocking, ","))
+	diff("query", info.Query, in.Query)
+	return changes
+}
@@ -0,0 +1,119 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/cmd/connector/controllers"
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestCheckerControllerImpl_PlanCheckers(t *testing.T) {
+	// Arrange
+	specs := []types.CheckerSpec{
+		{Repository: "new-repo", Prefix: "lint"},
+		{Repository: "changed-repo", Prefix: "lint", Query: "status:open branch:master"},
+		{Repository: "same-repo", Prefix: "lint"},
+	}
+	uuids := map[string]string{}
+	for _, spec := range specs {
+		uuids[spec.Repository] = services.EncodeCheckerUUID(spec.Prefix, spec.Repository)
+	}
+	services.GerritChecker = checkerServiceMock{
+		checkerPrefixFn: func(uuid string) (string, bool) {
+			return "lint", true
+		},
+	}
+	registered := []*types.CheckerInfo{
+		{UUID: uuids["changed-repo"], Name: "lint", Description: "check source code formatting.",
+			Repository: "changed-repo", Status: "ENABLED", Query: "status:open"},
+		{UUID: uuids["same-repo"], Name: "lint", Description: "check source code formatting.",
+			Repository: "same-repo", Status: "ENABLED", Query: "status:open"},
+		{UUID: "jarvis:unmanaged", Name: "old", Repository: "old-repo", Status: "ENABLED", Query: "status:open"},
+		{UUID: "jarvis:disabled", Name: "older", Repository: "older-repo", Status: "DISABLED"},
+	}
+	var posted []string
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			body, err := json.Marshal(&registered)
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			posted = append(posted, pathing)
+			return []byte(")]}'{}"), nil
+		},
+	}
+
+	// Act
+	plan, err := controllers.Checker.PlanCheckers(specs)
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	applied, err := controllers.Checker.ApplyCheckers(plan)
+
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	if applied != len(plan) {
+		t.Errorf("expected every action to be applied, got: %d of %d", applied, len(plan))
+	}
+	var actions []string
+	for _, action := range plan {
+		actions = append(actions, action.Action+" "+action.Input.Repository)
+	}
+	expected := "create new-repo, update changed-repo, disable old-repo"
+	if strings.Join(actions, ", ") != expected {
+		t.Fatalf("expected plan %q, got: %q", expected, strings.Join(actions, ", "))
+	}
+	if len(plan[1].Changes) != 1 || !strings.HasPrefix(plan[1].Changes[0], "query:") {
+		t.Errorf("expected only the query to change, got: %v", plan[1].Changes)
+	}
+	expectedPaths := []string{
+		"a/plugins/checks/checkers/",
+		"a/plugins/checks/checkers/" + uuids["changed-repo"],
+		"a/plugins/checks/checkers/jarvis:unmanaged",
+	}
+	if strings.Join(posted, " ") != strings.Join(expectedPaths, " ") {
+		t.Errorf("expected requests %v, got: %v", expectedPaths, posted)
+	}
+}
+
+func TestCheckerControllerImpl_PlanMigration(t *testing.T) {
+	// Arrange
+	legacy := "jarvis:unit-tests-061bc62acb425af5bc8a4689221eed5781831ecc"
+	migrated := services.EncodeCheckerUUID("unit-tests", "MyProject")
+	registered := []*types.CheckerInfo{
+		{UUID: legacy, Name: "unit-tests", Repository: "MyProject", Status: "ENABLED", Query: "status:open",
+			Blocking: []string{"STATE_NOT_PASSING"}},
+		{UUID: services.EncodeCheckerUUID("lint", "MyProject"), Name: "lint", Repository: "MyProject",
+			Status: "ENABLED"},
+	}
+	services.GerritChecker = &services.GerritCheckerServiceImpl{}
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			body, err := json.Marshal(&registered)
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+
+	// Act
+	plan, err := controllers.Checker.PlanMigration()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	if len(plan) != 2 {
+		t.Fatalf("expected a create and a disable action, got: %d", len(plan))
+	}
+	if plan[0].Action != "create" || plan[0].Input.UUID != migrated || plan[0].Input.Status != "ENABLED" ||
+		strings.Join(plan[0].Input.Blocking, ",") != "STATE_NOT_PASSING" {
+		t.Errorf("expected the legacy checker to be copied under its new UUID, got: %+v", plan[0])
+	}
+	if plan[1].Action != "disable" || plan[1].Input.UUID != legacy || plan[1].Input.Status != "DISABLED" {
+		t.Errorf("expected the legacy checker to be disabled, got: %+v", plan[1])
+	}
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+var (
+	Checks checksController = &ChecksControllerImpl{}
+)
+
+type checksController interface {
+	ChangeChecks(changeID string) ([]*types.CheckInfo, error)
+	PrintChecks(w io.Writer, checks []*types.CheckInfo, output string) error
+}
+
+type ChecksControllerImpl struct{}
+
+// ChangeChecks returns the checks of our scheme on every patchset of a change, ordered by patchset and checker.
+func (controller *ChecksControllerImpl) ChangeChecks(changeID string) ([]*types.CheckInfo, error) {
+	change, err := services.GerritChanges.GetChange(changeID, "ALL_REVISIONS")
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []*types.CheckInfo
+	for revision := range change.Revisions {
+		revisionChecks, err := services.GerritChanges.GetChecks(changeID, revision)
+		if err != nil {
+			return nil, err
+		}
+		for _, check := range revisionChecks {
+			if strings.HasPrefix(check.CheckerUUID, checkerScheme+":") {
+				checks = append(checks, check)
+			}
+		}
+	}
+	sort.Slice(checks, func(i, j int) bool {
+		if checks[i].PatchSetID != checks[j].PatchSetID {
+			return checks[i].PatchSetID < checks[j].PatchSetID
+		}
+		return checks[i].CheckerUUID < checks[j].CheckerUUID
+	})
+
+	return checks, nil
+}
+
+// PrintChecks writes checks as a table, or as JSON.
+func (controller *ChecksControllerImpl) PrintChecks(w io.Writer, checks []*types.CheckInfo, output string) error {
+	switch output {
+	case OutputJSON:
+		if checks == nil {
+			checks = []*types.CheckInfo{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(checks)
+	case OutputTable:
+	default:
+		return fmt.Errorf("unknown output format %q", output)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATCHSET\tCHECKER\tSTATE\tSTARTED\tFINISHED\tDURATION\tMESSAGE\tURL")
+	for _, check := range checks {
+		name := check.CheckerUUID
+		if prefix, _, ok := services.DecodeCheckerUUID(check.CheckerUUID); ok {
+			name = prefix
+		}
+		started, finished, duration := "", "", ""
+		if !time.Time(check.Started).IsZero() {
+			started = formatTime(check.Started)
+		}
+		if !time.Time(check.Finished).IsZero() {
+			finished = formatTime(check.Finished)
+			if started != "" {
+				duration = time.Time(check.Finished).Sub(time.Time(check.Started)).String()
+			}
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", check.PatchSetID, name, check.State, started, finished,
+			duration, strings.Join(strings.Fields(check.Message), " "), check.URL)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,77 @@
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/cmd/connector/controllers"
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestChecksControllerImpl_ChangeChecks(t *testing.T) {
+	// Arrange
+	lint := services.EncodeCheckerUUID("lint", "MyProject")
+	started := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			body, err := json.Marshal(&types.PendingSubmitInfo{ChangeNumber: 10, CurrentRevision: "b",
+				Revisions: map[string]types.Revision{"a": {Number: 1}, "b": {Number: 2}}})
+			return append([]byte(")]}'"), body...), err
+		},
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			patchset := 1
+			if strings.Contains(pathing, "/revisions/b/") {
+				patchset = 2
+			}
+			body, err := json.Marshal([]*types.CheckInfo{
+				{PatchSetID: patchset, CheckerUUID: lint, State: "SUCCESSFUL", Message: "all\ngood",
+					URL: "https://tekton.local/run/1", Started: types.Timestamp(started),
+					Finished: types.Timestamp(started.Add(90 * time.Second))},
+				{PatchSetID: patchset, CheckerUUID: "other:checker", State: "FAILED"},
+			})
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+
+	// Act
+	checks, err := controllers.Checks.ChangeChecks("10")
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	var table, out bytes.Buffer
+	tableErr := controllers.Checks.PrintChecks(&table, checks, controllers.OutputTable)
+	jsonErr := controllers.Checks.PrintChecks(&out, checks, controllers.OutputJSON)
+
+	// Assert
+	if tableErr != nil || jsonErr != nil {
+		t.Fatalf("resulting errors expected to be nil, received: %v, %v", tableErr, jsonErr)
+	}
+	if len(checks) != 2 || checks[0].PatchSetID != 1 || checks[1].PatchSetID != 2 {
+		t.Fatalf("expected the jarvis check of both patchsets in order, got: %d checks", len(checks))
+	}
+	lines := strings.Split(strings.TrimSpace(table.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "PATCHSET") {
+		t.Fatalf("expected a header and 2 rows, got:\n%s", table.String())
+	}
+	for _, expected := range []string{"lint", "SUCCESSFUL", "1m30s", "all good", "https://tekton.local/run/1"} {
+		if !strings.Contains(lines[1], expected) {
+			t.Errorf("expected %q in row %q", expected, lines[1])
+		}
+	}
+	var decoded []*types.CheckInfo
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded) != 2 ||
+		decoded[0].URL != "https://tekton.local/run/1" {
+		t.Errorf("expected the checks as JSON, got: %s (%v)", out.String(), err)
+	}
+	if err := controllers.Checks.PrintChecks(&out, checks, "yaml"); err == nil {
+		t.Errorf("expected an error for an unknown output format")
+	}
+}
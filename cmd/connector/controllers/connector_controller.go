@@ -10,20 +10,25 @@ import (
 
 var (
 	Connector connectorController = &ConnectorControllerImpl{
-		pendingCheck:  make(chan *types.PendingChecksInfo, 5),
-		pendingSubmit: make(chan *types.PendingSubmitInfo, 5),
+		pendingCheck:   make(chan *types.PendingChecksInfo, 5),
+		pendingSubmit:  make(chan *types.PendingSubmitInfo, 5),
+		prioritySubmit: make(chan *types.PendingSubmitInfo, 5),
+		pendingMerged:  make(chan *types.PendingSubmitInfo, 5),
 	}
 )
 
 type connectorController interface {
 	ServeCheck()
 	ServeSubmit()
+	ServeMerged()
 	PendingLoop()
 }
 
 type ConnectorControllerImpl struct {
-	pendingCheck  chan *types.PendingChecksInfo
-	pendingSubmit chan *types.PendingSubmitInfo
+	pendingCheck   chan *types.PendingChecksInfo
+	pendingSubmit  chan *types.PendingSubmitInfo
+	prioritySubmit chan *types.PendingSubmitInfo
+	pendingMerged  chan *types.PendingSubmitInfo
 }
 
 // ServeCheck runs the serve loop, dispatching for checks that need it.
@@ -36,9 +41,20 @@ func (controller *ConnectorControllerImpl) ServeCheck() {
 	}
 }
 
-// ServeSubmit runs the serve loop, dispatching for submissions that need it.
+// ServeSubmit runs the serve loop, dispatching for submissions that need it. Priority submissions are always
+// dispatched before the others.
 func (controller *ConnectorControllerImpl) ServeSubmit() {
-	for p := range controller.pendingSubmit {
+	for {
+		var p *types.PendingSubmitInfo
+		select {
+		case p = <-controller.prioritySubmit:
+		default:
+			select {
+			case p = <-controller.prioritySubmit:
+			case p = <-controller.pendingSubmit:
+			}
+		}
+
 		// TODO: parallelism?.
 		if err := services.GerritSubmitter.ExecuteSubmit(p); err != nil {
 			log.Printf("ExecuteSubmit(%v): %v", p, err)
@@ -46,6 +62,18 @@ func (controller *ConnectorControllerImpl) ServeSubmit() {
 	}
 }
 
+// ServeMerged runs the serve loop, dispatching for merged changes that need it.
+func (controller *ConnectorControllerImpl) ServeMerged() {
+	for p := range controller.pendingMerged {
+		if err := services.GerritPostMerge.ExecutePostMerge(p); err != nil {
+			log.Printf("ExecutePostMerge(%v): %v", p.ChangeNumber, err)
+		}
+		if err := services.Backport.ExecuteBackport(p); err != nil {
+			log.Printf("ExecuteBackport(%v): %v", p.ChangeNumber, err)
+		}
+	}
+}
+
 // pendingLoop periodically contacts gerrit to find new checks and submissions to
 // execute. It should be executed in a goroutine.
 func (controller *ConnectorControllerImpl) PendingLoop() {
@@ -71,8 +99,12 @@ func (controller *ConnectorControllerImpl) PendingLoop() {
 		if err == nil {
 			log.Printf("Received %d Pending Submissions", len(pendingSubmissions))
 			for _, ps := range pendingSubmissions {
+				queue := controller.pendingSubmit
+				if ps.Priority {
+					queue = controller.prioritySubmit
+				}
 				select {
-				case controller.pendingSubmit <- ps:
+				case queue <- ps:
 				default:
 					log.Println("too busy; dropping submission.")
 				}
@@ -80,5 +112,25 @@ func (controller *ConnectorControllerImpl) PendingLoop() {
 		} else {
 			log.Printf("PendingSubmit: %v", err)
 		}
+
+		// Handle merged changes
+		if services.Config.PostMerge.Enabled || services.Config.Backport.Enabled {
+			controller.queueMerged()
+		}
+	}
+}
+
+// queueMerged queues the changes merged since the previous round. PendingMerged returns each merged change only
+// once, so the queue blocks rather than dropping any of them.
+func (controller *ConnectorControllerImpl) queueMerged() {
+	merged, err := services.GerritPostMerge.PendingMerged()
+	if err != nil {
+		log.Printf("PendingMerged: %v", err)
+		return
+	}
+
+	log.Printf("Received %d Merged Changes", len(merged))
+	for _, m := range merged {
+		controller.pendingMerged <- m
 	}
 }
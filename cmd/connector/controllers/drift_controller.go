@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+const (
+	driftMissing   = "missing"
+	driftDisabled  = "disabled"
+	driftChanged   = "changed"
+	driftUnmanaged = "unmanaged"
+)
+
+var (
+	Drift driftController = &DriftControllerImpl{}
+
+	// driftKinds are the kinds of drift, in the order they are reported in.
+	driftKinds = []string{driftMissing, driftDisabled, driftChanged, driftUnmanaged}
+)
+
+type driftController interface {
+	Run()
+	Check() ([]*types.CheckerDrift, error)
+	WriteMetrics(w io.Writer) error
+}
+
+type DriftControllerImpl struct {
+	mu sync.Mutex
+	// drifts counts the drifted checkers of the latest check, by kind.
+	drifts      map[string]int
+	checks      int
+	errors      int
+	corrections int
+	lastCheck   time.Time
+}
+
+// Run compares the registered checkers with the configured ones every configured interval. It should be executed
+// in a goroutine.
+func (controller *DriftControllerImpl) Run() {
+	interval, err := time.ParseDuration(services.Config.Drift.Interval)
+	if err != nil {
+		log.Printf("drift interval: %v", err)
+		return
+	}
+
+	for {
+		if _, err := controller.Check(); err != nil {
+			log.Printf("Drift Check: %v", err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Check compares the registered checkers with the configured ones, logs every drifted checker and records the
+// metrics. With auto correction enabled, drifted configured checkers are created or updated to match.
+func (controller *DriftControllerImpl) Check() ([]*types.CheckerDrift, error) {
+	plan, err := Checker.PlanCheckers(services.Config.Checkers)
+	if err != nil {
+		controller.record(nil, 0, err)
+		return nil, err
+	}
+
+	var drifts []*types.CheckerDrift
+	var corrections []*types.CheckerAction
+	for _, action := range plan {
+		drift := &types.CheckerDrift{
+			Kind:       driftKind(action),
+			UUID:       action.Input.UUID,
+			Repository: action.Input.Repository,
+			Changes:    action.Changes,
+		}
+		log.Printf("checker %s of %s drifted: %s %v", drift.UUID, drift.Repository, drift.Kind, drift.Changes)
+		drifts = append(drifts, drift)
+		if drift.Kind != driftUnmanaged {
+			corrections = append(corrections, action)
+		}
+	}
+
+	corrected := 0
+	if services.Config.Drift.AutoCorrect && len(corrections) > 0 {
+		corrected, err = Checker.ApplyCheckers(corrections)
+	}
+	controller.record(drifts, corrected, err)
+
+	return drifts, err
+}
+
+// driftKind tells how a checker drifted from the action that corrects it.
+func driftKind(action *types.CheckerAction) string {
+	switch action.Action {
+	case checkerActionCreate:
+		return driftMissing
+	case checkerActionDisable:
+		return driftUnmanaged
+	}
+	for _, change := range action.Changes {
+		if strings.HasPrefix(change, "status:") && action.Input.Status == services.CheckerEnabledString {
+			return driftDisabled
+		}
+	}
+	return driftChanged
+}
+
+// record updates the metrics with the outcome of a check.
+func (controller *DriftControllerImpl) record(drifts []*types.CheckerDrift, corrected int, err error) {
+	controller.mu.Lock()
+	defer controller.mu.Unlock()
+
+	controller.checks++
+	controller.lastCheck = time.Now()
+	if err != nil {
+		controller.errors++
+		if drifts == nil {
+			return
+		}
+	}
+	controller.corrections += corrected
+	controller.drifts = map[string]int{}
+	for _, drift := range drifts {
+		controller.drifts[drift.Kind]++
+	}
+}
+
+// WriteMetrics writes the drift metrics in the Prometheus text format.
+func (controller *DriftControllerImpl) WriteMetrics(w io.Writer) error {
+	controller.mu.Lock()
+	defer controller.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP jarvis_checker_drift Registered checkers that differ from the configuration, by kind.\n")
+	b.WriteString("# TYPE jarvis_checker_drift gauge\n")
+	for _, kind := range driftKinds {
+		fmt.Fprintf(&b, "jarvis_checker_drift{kind=%q} %d\n", kind, controller.drifts[kind])
+	}
+	b.WriteString("# HELP jarvis_checker_drift_checks_total Comparisons of the registered checkers with the " +
+		"configuration.\n")
+	b.WriteString("# TYPE jarvis_checker_drift_checks_total counter\n")
+	fmt.Fprintf(&b, "jarvis_checker_drift_checks_total %d\n", controller.checks)
+	b.WriteString("# HELP jarvis_checker_drift_errors_total Comparisons or corrections that failed.\n")
+	b.WriteString("# TYPE jarvis_checker_drift_errors_total counter\n")
+	fmt.Fprintf(&b, "jarvis_checker_drift_errors_total %d\n", controller.errors)
+	b.WriteString("# HELP jarvis_checker_drift_corrections_total Checkers corrected automatically.\n")
+	b.WriteString("# TYPE jarvis_checker_drift_corrections_total counter\n")
+	fmt.Fprintf(&b, "jarvis_checker_drift_corrections_total %d\n", controller.corrections)
+	b.WriteString("# HELP jarvis_checker_drift_last_check_timestamp_seconds Time of the latest comparison.\n")
+	b.WriteString("# TYPE jarvis_checker_drift_last_check_timestamp_seconds gauge\n")
+	lastCheck := int64(0)
+	if !controller.lastCheck.IsZero() {
+		lastCheck = controller.lastCheck.Unix()
+	}
+	fmt.Fprintf(&b, "jarvis_checker_drift_last_check_timestamp_seconds %d\n", lastCheck)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,129 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/cmd/connector/controllers"
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestDriftControllerImpl_Check(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Drift.AutoCorrect = true
+	services.Config.Checkers = []types.CheckerSpec{
+		{Repository: "missing", Prefix: "lint"},
+		{Repository: "disabled", Prefix: "lint"},
+		{Repository: "changed", Prefix: "lint", Query: "status:open branch:master"},
+		{Repository: "same", Prefix: "lint"},
+	}
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	registered := []*types.CheckerInfo{
+		{UUID: services.EncodeCheckerUUID("lint", "disabled"), Name: "lint", Repository: "disabled",
+			Description: "check source code formatting.", Status: "DISABLED", Query: "status:open"},
+		{UUID: services.EncodeCheckerUUID("lint", "changed"), Name: "lint", Repository: "changed",
+			Description: "check source code formatting.", Status: "ENABLED", Query: "status:open"},
+		{UUID: services.EncodeCheckerUUID("lint", "same"), Name: "lint", Repository: "same",
+			Description: "check source code formatting.", Status: "ENABLED", Query: "status:open"},
+		{UUID: services.EncodeCheckerUUID("lint", "unmanaged"), Name: "lint", Repository: "unmanaged",
+			Status: "ENABLED"},
+	}
+	var posted []string
+	services.GerritChecker = &services.GerritCheckerServiceImpl{}
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			body, err := json.Marshal(&registered)
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			var in types.CheckerInput
+			err := json.Unmarshal(content, &in)
+			posted = append(posted, in.Repository)
+			return []byte(")]}'{}"), err
+		},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+
+	// Act
+	drifts, err := controllers.Drift.Check()
+	controllers.Callback.Handler().ServeHTTP(rec, req)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	var kinds []string
+	for _, drift := range drifts {
+		kinds = append(kinds, drift.Repository+"="+drift.Kind)
+	}
+	expected := "missing=missing disabled=disabled changed=changed unmanaged=unmanaged"
+	if strings.Join(kinds, " ") != expected {
+		t.Errorf("expected drifts %q, got: %q", expected, strings.Join(kinds, " "))
+	}
+	if strings.Join(posted, " ") != "missing disabled changed" {
+		t.Errorf("expected only the configured checkers to be corrected, got: %v", posted)
+	}
+	metrics := rec.Body.String()
+	for _, line := range []string{`jarvis_checker_drift{kind="missing"} 1`, `jarvis_checker_drift{kind="unmanaged"} 1`,
+		"jarvis_checker_drift_corrections_total 3", "# TYPE jarvis_checker_drift_checks_total counter"} {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("expected %q in metrics:\n%s", line, metrics)
+		}
+	}
+}
+
+func TestDriftControllerImpl_CheckPartialCorrection(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Drift.AutoCorrect = true
+	services.Config.Checkers = []types.CheckerSpec{
+		{Repository: "first", Prefix: "lint"},
+		{Repository: "broken", Prefix: "lint"},
+		{Repository: "last", Prefix: "lint"},
+	}
+	defer func() { services.Config = services.DefaultConfig() }()
+	drift := controllers.Drift
+	controllers.Drift = &controllers.DriftControllerImpl{}
+	defer func() { controllers.Drift = drift }()
+
+	services.GerritChecker = &services.GerritCheckerServiceImpl{}
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			return []byte(")]}'[]"), nil
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			var in types.CheckerInput
+			if err := json.Unmarshal(content, &in); err != nil {
+				return nil, err
+			}
+			if in.Repository == "broken" {
+				return nil, &services.StatusError{StatusCode: http.StatusConflict}
+			}
+			return []byte(")]}'{}"), nil
+		},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+
+	// Act
+	_, err := controllers.Drift.Check()
+	controllers.Callback.Handler().ServeHTTP(rec, req)
+
+	// Assert
+	if err == nil {
+		t.Errorf("expected the failed correction to be reported")
+	}
+	metrics := rec.Body.String()
+	for _, line := range []string{"jarvis_checker_drift_corrections_total 1", "jarvis_checker_drift_errors_total 1"} {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("expected %q in metrics:\n%s", line, metrics)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+var (
+	Explain explainController = &ExplainControllerImpl{}
+)
+
+type explainController interface {
+	Explain(w io.Writer, changeID, output string) error
+}
+
+type ExplainControllerImpl struct{}
+
+// Explain writes how a change fares against every submit condition, as a table or as JSON.
+func (controller *ExplainControllerImpl) Explain(w io.Writer, changeID, output string) error {
+	if output != OutputTable && output != OutputJSON {
+		return fmt.Errorf("unknown output format %q", output)
+	}
+	explanation, err := services.GerritSubmitter.Explain(changeID)
+	if err != nil {
+		return err
+	}
+
+	if output == OutputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(explanation)
+	}
+	return printExplanation(w, explanation)
+}
+
+// printExplanation writes an explanation as plain text tables.
+func printExplanation(w io.Writer, explanation *types.SubmitExplanation) error {
+	verdict := "will not be submitted"
+	if explanation.Submittable {
+		verdict = "will be submitted"
+	}
+	fmt.Fprintf(w, "change %d on %s %s (%s) %s\n\n", explanation.ChangeNumber, explanation.Project,
+		explanation.Branch, explanation.Status, verdict)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONDITION\tRESULT\tDETAILS")
+	for _, condition := range explanation.Conditions {
+		result := "FAIL"
+		if condition.Passed {
+			result = "PASS"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", condition.Name, result, condition.Details)
+	}
+	fmt.Fprintln(tw)
+
+	names := make([]string, 0, len(explanation.Labels))
+	for name := range explanation.Labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(tw, "LABEL\tAPPROVED BY\tREJECTED BY\tBLOCKING")
+	for _, name := range names {
+		label := explanation.Labels[name]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\n", name, accountID(label.Approved), accountID(label.Rejected),
+			label.Blocking)
+	}
+	return tw.Flush()
+}
+
+// accountID formats the account of an approval, if any.
+func accountID(approval types.Approval) string {
+	if approval.AccountID == 0 {
+		return ""
+	}
+	return fmt.Sprint(approval.AccountID)
+}
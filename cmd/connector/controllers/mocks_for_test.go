@@ -10,6 +10,7 @@ import (
 type serverServiceMock struct {
 	postPathFn    func(pathing string, headers []types.Header, content []byte) ([]byte, error)
 	getPathFn     func(pathing string, headers []types.Header) ([]byte, error)
+	deletePathFn  func(pathing string, headers []types.Header) ([]byte, error)
 	getFn         func(u *url.URL) ([]byte, error)
 	initFn        func(url url.URL, authenticator services.Authenticator, testPath string)
 	getURLFn      func() url.URL
@@ -24,6 +25,10 @@ func (s serverServiceMock) PostPath(pathing string, headers []types.Header, cont
 	return s.postPathFn(pathing, headers, content)
 }
 
+func (s serverServiceMock) DeletePath(pathing string, headers []types.Header) ([]byte, error) {
+	return s.deletePathFn(pathing, headers)
+}
+
 func (s serverServiceMock) Get(u *url.URL) ([]byte, error) {
 	return s.getFn(u)
 }
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"log"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/services"
+)
+
+var (
+	Scheduler schedulerController = &SchedulerControllerImpl{}
+)
+
+type schedulerController interface {
+	Run()
+}
+
+type SchedulerControllerImpl struct{}
+
+// Run starts the periodic jobs whose schedule fires, once every minute. It should be executed in a goroutine.
+func (controller *SchedulerControllerImpl) Run() {
+	for {
+		now := time.Now()
+		// Wake up at the start of the next minute.
+		time.Sleep(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+
+		for _, job := range services.Periodic.DuePeriodic(time.Now()) {
+			if err := services.Periodic.ExecutePeriodic(job); err != nil {
+				log.Printf("ExecutePeriodic(%q): %v", job.Name, err)
+			}
+		}
+	}
+}
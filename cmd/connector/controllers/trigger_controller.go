@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+var (
+	Trigger triggerController = &TriggerControllerImpl{}
+)
+
+type triggerController interface {
+	Check(changeID string, patchset int, uuid string) error
+	Submit(changeID string, patchset int, force bool) error
+}
+
+type TriggerControllerImpl struct{}
+
+// Check runs the checker given by uuid, or every enabled checker of ours on the change's repository if uuid is
+// empty, once on a patchset of a change. The current patchset is checked if patchset is 0.
+func (controller *TriggerControllerImpl) Check(changeID string, patchset int, uuid string) error {
+	change, err := services.GerritChanges.GetChange(changeID, "ALL_REVISIONS")
+	if err != nil {
+		return err
+	}
+	psID, err := triggerPatchset(change, patchset)
+	if err != nil {
+		return err
+	}
+
+	pending := map[string]*types.PendingCheckInfo{}
+	if uuid != "" {
+		pending[uuid] = &types.PendingCheckInfo{State: services.NotStartedString}
+	} else {
+		checkers, err := jarvisCheckers()
+		if err != nil {
+			return err
+		}
+		for _, checker := range checkers {
+			if checker.Repository == change.Project && checker.Status == services.CheckerEnabledString {
+				pending[checker.UUID] = &types.PendingCheckInfo{State: services.NotStartedString}
+			}
+		}
+		if len(pending) == 0 {
+			return fmt.Errorf("no enabled %s checker on repository %q", checkerScheme, change.Project)
+		}
+	}
+
+	return services.GerritChecker.ExecuteCheck(&types.PendingChecksInfo{
+		PatchSet: &types.CheckablePatchSetInfo{
+			Repository:   change.Project,
+			ChangeNumber: change.ChangeNumber,
+			PatchSetID:   psID,
+		},
+		PendingChecks: pending,
+	})
+}
+
+// Submit runs the submission of a change once, together with the other changes of its topic. Changes that do not
+// meet the submit conditions are refused unless force is set. Only the current patchset can be submitted, patchset
+// is 0 or its number.
+func (controller *TriggerControllerImpl) Submit(changeID string, patchset int, force bool) error {
+	if patchset != 0 {
+		change, err := services.GerritChanges.GetChange(changeID, "CURRENT_REVISION")
+		if err != nil {
+			return err
+		}
+		if current := change.Revisions[change.CurrentRevision].Number; patchset != current {
+			return fmt.Errorf("only the current patchset %d of change %s can be submitted", current, changeID)
+		}
+	}
+
+	change, err := services.GerritSubmitter.SubmitInfo(changeID, force)
+	if err != nil {
+		return err
+	}
+	return services.GerritSubmitter.ExecuteSubmit(change)
+}
+
+// triggerPatchset returns the number of the requested patchset of a change, the current one if patchset is 0.
+func triggerPatchset(change *types.PendingSubmitInfo, patchset int) (int, error) {
+	if patchset == 0 {
+		return change.Revisions[change.CurrentRevision].Number, nil
+	}
+	for _, revision := range change.Revisions {
+		if revision.Number == patchset {
+			return patchset, nil
+		}
+	}
+	return 0, fmt.Errorf("change %d has no patchset %d", change.ChangeNumber, patchset)
+}
@@ -0,0 +1,96 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/cmd/connector/controllers"
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestTriggerControllerImpl_Check(t *testing.T) {
+	// Arrange
+	lint := services.EncodeCheckerUUID("lint", "MyProject")
+	unit := services.EncodeCheckerUUID("unit", "MyProject")
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			body, err := json.Marshal(&types.PendingSubmitInfo{Project: "MyProject", ChangeNumber: 10,
+				CurrentRevision: "b", Revisions: map[string]types.Revision{"a": {Number: 1}, "b": {Number: 2}}})
+			return append([]byte(")]}'"), body...), err
+		},
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			body, err := json.Marshal([]*types.CheckerInfo{
+				{UUID: lint, Repository: "MyProject", Status: "ENABLED"},
+				{UUID: unit, Repository: "MyProject", Status: "DISABLED"},
+				{UUID: services.EncodeCheckerUUID("lint", "Other"), Repository: "Other", Status: "ENABLED"},
+			})
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+	var checked []*types.PendingChecksInfo
+	services.GerritChecker = checkerServiceMock{
+		checkerPrefixFn: func(uuid string) (string, bool) {
+			return "lint", true
+		},
+		executeCheckFn: func(pc *types.PendingChecksInfo) error {
+			checked = append(checked, pc)
+			return nil
+		},
+	}
+
+	// Act
+	errCurrent := controllers.Trigger.Check("10", 0, "")
+	errPatchset := controllers.Trigger.Check("10", 1, unit)
+	errMissing := controllers.Trigger.Check("10", 3, "")
+
+	// Assert
+	if errCurrent != nil || errPatchset != nil {
+		t.Fatalf("resulting errors expected to be nil, received: %v, %v", errCurrent, errPatchset)
+	}
+	if errMissing == nil {
+		t.Errorf("expected an error for a missing patchset")
+	}
+	if len(checked) != 2 {
+		t.Fatalf("expected 2 checks to be executed, got: %d", len(checked))
+	}
+	if checked[0].PatchSet.PatchSetID != 2 || len(checked[0].PendingChecks) != 1 ||
+		checked[0].PendingChecks[lint] == nil {
+		t.Errorf("expected the enabled checker to run on the current patchset, got: %s %v", checked[0].PatchSet,
+			checked[0].PendingChecks)
+	}
+	if checked[1].PatchSet.PatchSetID != 1 || checked[1].PendingChecks[unit] == nil {
+		t.Errorf("expected the given checker to run on the given patchset, got: %s %v", checked[1].PatchSet,
+			checked[1].PendingChecks)
+	}
+}
+
+func TestTriggerControllerImpl_SubmitOutdatedPatchset(t *testing.T) {
+	// Arrange
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			body, err := json.Marshal(&types.PendingSubmitInfo{ChangeNumber: 10, CurrentRevision: "b",
+				Revisions: map[string]types.Revision{"b": {Number: 2}}})
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			t.Errorf("expected nothing to be posted, got: %s", pathing)
+			return nil, nil
+		},
+	}
+
+	// Act
+	err := controllers.Trigger.Submit("10", 1, false)
+
+	// Assert
+	if err == nil {
+		t.Errorf("expected an error submitting an outdated patchset")
+	}
+}
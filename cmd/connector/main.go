@@ -20,9 +20,12 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/att-comdev/jarvis-connector/cmd/connector/controllers"
 	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
 
 	flag "github.com/spf13/pflag"
 )
@@ -34,10 +37,44 @@ var (
 	EventListenerURL string
 	register         bool
 	update           bool
+	registerAll      bool
+	projectRegex     string
+	parent           string
 	list             bool
+	show             bool
+	enable           bool
+	disable          bool
+	archive          bool
+	deleteChecker    bool
+	uuid             string
+	trigger          bool
+	triggerSubmit    bool
+	force            bool
+	change           string
+	patchset         int
+	checks           bool
+	explain          bool
+	output           string
+	backfill         bool
+	backfillInterval time.Duration
+	plan             bool
+	apply            bool
+	migrateUUIDs     bool
+	dryRun           bool
 	authFile         string
+	configFile       string
+	submitQuery      string
+	submitMode       string
+	listenAddr       string
+	callbackAuthFile string
+	requireChecks    bool
 	repo             string
 	prefix           string
+	description      string
+	checkerURL       string
+	blocking         []string
+	query            string
+	status           string
 )
 
 func main() {
@@ -46,14 +83,68 @@ func main() {
 	flag.StringVar(&EventListenerURL, "event_listener", "", "URL of the Tekton EventListener")
 	flag.BoolVar(&register, "register", false, "Register the connector with gerrit")
 	flag.BoolVar(&update, "update", false, "Update an existing check")
-	flag.BoolVar(&list, "list", false, "List pending checks")
+	flag.BoolVar(&registerAll, "register_all", false, "Register or update the checker on every matching project")
+	flag.StringVar(&projectRegex, "project_regex", "", "the regex project names must match for --register_all")
+	flag.StringVar(&parent, "parent", "", "the parent project of the projects for --register_all")
+	flag.BoolVar(&list, "list", false, "List the checkers, filtered by --repo, --status and --prefix")
+	flag.BoolVar(&show, "show", false, "Show the checker given by --uuid")
+	flag.BoolVar(&enable, "enable", false, "Enable the checker given by --uuid")
+	flag.BoolVar(&disable, "disable", false, "Disable the checker given by --uuid")
+	flag.BoolVar(&archive, "archive", false, "Disable the checker given by --uuid and mark it as archived")
+	flag.BoolVar(&deleteChecker, "delete", false, "Delete the checker given by --uuid")
+	flag.BoolVar(&trigger, "trigger", false, "Check the change given by --change once, printing every request")
+	flag.BoolVar(&triggerSubmit, "submit", false, "With --trigger, submit the change instead of checking it")
+	flag.BoolVar(&force, "force", false, "With --trigger --submit, submit even if the submit conditions are not met")
+	flag.StringVar(&change, "change", "", "the change number to act on")
+	flag.IntVar(&patchset, "patchset", 0, "the patchset of --change to act on, the current one if unset")
+	flag.BoolVar(&checks, "checks", false, "Show the jarvis checks of the change given by --change")
+	flag.BoolVar(&explain, "explain", false, "Explain whether and why the change given by --change will be submitted")
+	flag.StringVar(
+		&output,
+		"output",
+		controllers.OutputTable,
+		"the output format of --list, --checks and --explain: table or json, or yaml with --list")
+	flag.BoolVar(&backfill, "backfill", false, "Check the open changes the checker given by --uuid applies to")
+	flag.DurationVar(&backfillInterval, "backfill_interval", 2*time.Second, "the time between checks of --backfill")
+	flag.BoolVar(&plan, "plan", false, "Show how the registered checkers differ from the checkers in --config")
+	flag.BoolVar(&apply, "apply", false, "Create, update and disable checkers to match the checkers in --config")
+	flag.BoolVar(&migrateUUIDs, "migrate_uuids", false, "Re-register checkers with legacy UUIDs under the current scheme")
+	flag.BoolVar(&dryRun, "dry_run", false, "Only show what --migrate_uuids, --register_all or --backfill would do")
+	flag.StringVar(&uuid, "uuid", "", "the UUID of the checker to act on, e.g. with --show, --backfill or --trigger.")
 	flag.StringVar(&authFile, "auth_file", "", "file containing user:password")
+	flag.StringVar(&configFile, "config", "", "JSON file containing the connector configuration")
+	flag.StringVar(&submitQuery, "submit_query", "", "Gerrit query selecting the changes to submit, overrides --config")
+	flag.StringVar(
+		&submitMode,
+		"submit_mode",
+		"",
+		"who submits changes after the merge pipeline succeeded: pipeline or connector, overrides --config")
+	flag.BoolVar(&requireChecks, "require_checks", false, "only submit changes whose jarvis checks all passed")
+	flag.StringVar(&listenAddr, "listen", "", "address to serve the pipeline callback endpoints on, e.g. :8080")
+	flag.StringVar(&callbackAuthFile, "callback_token_file", "", "file containing the bearer token callbacks must carry")
 	flag.StringVar(&repo, "repo", "", "the repository (project) name to apply the checker to.")
 	flag.StringVar(
 		&prefix,
 		"prefix",
 		"",
 		"the prefix that the checker should use for jobs, this is also used as the job name in gerrit.")
+	flag.StringVar(&description, "description", "", "the description of the checker.")
+	flag.StringVar(&checkerURL, "url", "", "the URL of the checker, e.g. of its pipeline.")
+	flag.StringSliceVar(
+		&blocking,
+		"blocking",
+		nil,
+		"the conditions under which the checker blocks submission of a change, e.g. STATE_NOT_PASSING.")
+	flag.StringVar(
+		&query,
+		"query",
+		"",
+		"the query selecting the changes the checker applies to, status:open if unset on --register.")
+	flag.StringVar(
+		&status,
+		"status",
+		"",
+		"the status of the checker: ENABLED or DISABLED, ENABLED if unset on --register. With --list, any if unset.")
 	flag.Parse()
 
 	if GerritURL == "" {
@@ -72,15 +163,158 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if configFile != "" {
+		services.Config, err = services.LoadConfig(configFile)
+		if err != nil {
+			log.Fatalf("LoadConfig: %v", err)
+		}
+	}
+	if submitQuery != "" {
+		services.Config.Submit.Query = submitQuery
+	}
+	if requireChecks {
+		services.Config.Submit.RequireChecks = true
+	}
+	if submitMode != "" {
+		services.Config.Submit.Mode = submitMode
+	}
+	if services.Config.Submit.Mode != services.SubmitModePipeline &&
+		services.Config.Submit.Mode != services.SubmitModeConnector {
+		log.Fatalf("unknown submit mode %q", services.Config.Submit.Mode)
+	}
+
 	services.GerritServer.Init(*gerritURLObj, services.NewBasicAuth(string(authFileContent)), "a/accounts/self")
 
 	if list { //nolint
-		err = controllers.Checker.ListCheckers()
+		if output != controllers.OutputTable && output != controllers.OutputJSON && output != controllers.OutputYAML {
+			log.Fatalf("unknown --output %q, must be table, json or yaml", output)
+		}
 
+		filter := types.CheckerFilter{Repository: repo, Status: status, Prefix: prefix}
+		checkers, err := controllers.Checker.ListCheckers(filter)
 		if err != nil {
 			log.Fatalf("ListCheckers: %v", err)
 		}
 
+		if err := controllers.Checker.PrintCheckers(os.Stdout, checkers, output); err != nil {
+			log.Fatalf("PrintCheckers: %v", err)
+		}
+
+		os.Exit(0)
+	} else if migrateUUIDs {
+		actions, err := controllers.Checker.PlanMigration()
+		if err != nil {
+			log.Fatalf("PlanMigration: %v", err)
+		}
+
+		if err := runPlan(actions, !dryRun); err != nil {
+			log.Fatalf("migrateUUIDs: %v", err)
+		}
+
+		os.Exit(0)
+	} else if checks {
+		if change == "" {
+			log.Fatalf("must set --change")
+		}
+
+		changeChecks, err := controllers.Checks.ChangeChecks(change)
+		if err != nil {
+			log.Fatalf("ChangeChecks: %v", err)
+		}
+		if err := controllers.Checks.PrintChecks(os.Stdout, changeChecks, output); err != nil {
+			log.Fatalf("PrintChecks: %v", err)
+		}
+
+		os.Exit(0)
+	} else if explain {
+		if change == "" {
+			log.Fatalf("must set --change")
+		}
+
+		if err := controllers.Explain.Explain(os.Stdout, change, output); err != nil {
+			log.Fatalf("Explain: %v", err)
+		}
+
+		os.Exit(0)
+	} else if trigger {
+		if change == "" {
+			log.Fatalf("must set --change")
+		}
+
+		initEventListener()
+		services.GerritServer = services.NewTracingServer("gerrit", services.GerritServer)
+		services.EventListenerServer = services.NewTracingServer("event_listener", services.EventListenerServer)
+		if triggerSubmit {
+			err = controllers.Trigger.Submit(change, patchset, force)
+		} else {
+			err = controllers.Trigger.Check(change, patchset, uuid)
+		}
+		if err != nil {
+			log.Fatalf("Trigger: %v", err)
+		}
+
+		os.Exit(0)
+	} else if backfill {
+		if uuid == "" {
+			log.Fatalf("must set --uuid")
+		}
+		if backfillInterval <= 0 {
+			log.Fatalf("--backfill_interval must be positive")
+		}
+
+		if !dryRun {
+			initEventListener()
+		}
+		n, err := controllers.Backfill.Run(uuid, backfillInterval, dryRun)
+		if err != nil {
+			log.Fatalf("Backfill: %v", err)
+		}
+		log.Printf("dispatched %d checks", n)
+
+		os.Exit(0)
+	} else if plan || apply {
+		if configFile == "" {
+			log.Fatalf("must set --config")
+		}
+
+		if err := reconcileCheckers(apply); err != nil {
+			log.Fatalf("reconcileCheckers: %v", err)
+		}
+
+		os.Exit(0)
+	} else if show || enable || disable || archive || deleteChecker {
+		if uuid == "" {
+			log.Fatalf("must set --uuid")
+		}
+
+		if err := manageChecker(uuid); err != nil {
+			log.Fatalf("%s: %v", uuid, err)
+		}
+
+		os.Exit(0)
+	} else if registerAll {
+		if prefix == "" {
+			log.Fatalf("must set --prefix")
+		}
+
+		if projectRegex == "" && parent == "" {
+			log.Fatalf("must set --project_regex or --parent")
+		}
+
+		summary, err := controllers.Checker.RegisterAll(checkerSpec(), projectRegex, parent, dryRun)
+		if err != nil {
+			log.Fatalf("RegisterAll: %v", err)
+		}
+		log.Printf("created %d: %v", len(summary.Created), summary.Created)
+		log.Printf("updated %d: %v", len(summary.Updated), summary.Updated)
+		log.Printf("skipped %d: %v", len(summary.Skipped), summary.Skipped)
+		if dryRun {
+			log.Printf("dry run, no checkers were changed")
+		}
+		if len(summary.Failed) > 0 {
+			log.Fatalf("failed %d: %v", len(summary.Failed), summary.Failed)
+		}
+
 		os.Exit(0)
 	} else if register || update {
 		if repo == "" {
@@ -91,7 +325,7 @@ func main() {
 			log.Fatalf("must set --prefix")
 		}
 
-		ch, err := controllers.Checker.PostChecker(repo, prefix, update)
+		ch, err := controllers.Checker.PostChecker(checkerSpec(), update)
 		if err != nil {
 			log.Fatalf("CreateChecker: %v", err)
 		}
@@ -99,18 +333,130 @@ func main() {
 
 		os.Exit(0)
 	} else {
-		if EventListenerURL == "" {
-			log.Fatal("must set --event_listener")
-		}
-		eventListenerURLObj, err := url.Parse(EventListenerURL)
-		if err != nil {
-			log.Fatal(err)
-		}
+		initEventListener()
 
-		services.EventListenerServer.Init(*eventListenerURLObj, nil, "/")
+		if listenAddr != "" {
+			controllers.Callback = &controllers.CallbackControllerImpl{Token: readCallbackToken()}
+			go func() {
+				log.Fatalf("ListenAndServe: %v", controllers.Callback.ListenAndServe(listenAddr))
+			}()
+		} else if services.Config.Submit.Mode == services.SubmitModeConnector {
+			log.Fatal("must set --listen when the connector submits changes")
+		} else if len(services.Config.Periodic) > 0 {
+			log.Fatal("must set --listen when periodic jobs are configured")
+		} else if services.Config.Drift.Enabled {
+			log.Fatal("must set --listen to serve the drift metrics")
+		}
 
 		go controllers.Connector.ServeCheck()
 		go controllers.Connector.ServeSubmit()
+		go controllers.Connector.ServeMerged()
+		if len(services.Config.Periodic) > 0 {
+			go controllers.Scheduler.Run()
+		}
+		if services.Config.Drift.Enabled {
+			go controllers.Drift.Run()
+		}
 		controllers.Connector.PendingLoop()
 	}
 }
+
+// initEventListener sets up the connection to the Tekton EventListener.
+func initEventListener() {
+	if EventListenerURL == "" {
+		log.Fatal("must set --event_listener")
+	}
+	eventListenerURLObj, err := url.Parse(EventListenerURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	services.EventListenerServer.Init(*eventListenerURLObj, nil, "/")
+}
+
+// readCallbackToken returns the bearer token the callback endpoints require.
+func readCallbackToken() string {
+	if callbackAuthFile == "" {
+		log.Fatal("must set --callback_token_file with --listen")
+	}
+	content, err := ioutil.ReadFile(callbackAuthFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		log.Fatalf("%s does not contain a token", callbackAuthFile)
+	}
+	return token
+}
+
+// checkerSpec returns the checker described by the flags.
+func checkerSpec() types.CheckerSpec {
+	return types.CheckerSpec{
+		Repository:  repo,
+		Prefix:      prefix,
+		Description: description,
+		URL:         checkerURL,
+		Query:       query,
+		Blocking:    blocking,
+		Status:      status,
+	}
+}
+
+// manageChecker runs the lifecycle command selected by the flags on a checker.
+func manageChecker(uuid string) error {
+	var ch *types.CheckerInfo
+	var err error
+	switch {
+	case show:
+		ch, err = controllers.Checker.GetChecker(uuid)
+	case enable:
+		ch, err = controllers.Checker.SetCheckerStatus(uuid, services.CheckerEnabledString)
+	case disable:
+		ch, err = controllers.Checker.SetCheckerStatus(uuid, services.CheckerDisabledString)
+	case archive:
+		ch, err = controllers.Checker.ArchiveChecker(uuid)
+	case deleteChecker:
+		if err := controllers.Checker.DeleteChecker(uuid); err != nil {
+			return err
+		}
+		log.Printf("deleted checker %s", uuid)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%v", ch)
+	return nil
+}
+
+// reconcileCheckers shows the plan reconciling the registered checkers with the configured ones, and applies it if
+// asked to.
+func reconcileCheckers(apply bool) error {
+	actions, err := controllers.Checker.PlanCheckers(services.Config.Checkers)
+	if err != nil {
+		return err
+	}
+	return runPlan(actions, apply)
+}
+
+// runPlan shows the actions of a plan, and carries them out if asked to.
+func runPlan(actions []*types.CheckerAction, apply bool) error {
+	if len(actions) == 0 {
+		log.Printf("checkers are up to date")
+		return nil
+	}
+
+	for _, action := range actions {
+		log.Printf("%s %s (%s)", action.Action, action.Input.UUID, action.Input.Repository)
+		for _, change := range action.Changes {
+			log.Printf("    %s", change)
+		}
+	}
+	if !apply {
+		return nil
+	}
+	_, err := controllers.Checker.ApplyCheckers(actions)
+	return err
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+var (
+	Backport backportService = &BackportServiceImpl{}
+)
+
+type backportService interface {
+	ExecuteBackport(change *types.PendingSubmitInfo) error
+}
+
+type BackportServiceImpl struct{}
+
+// ExecuteBackport cherry-picks a merged change onto every branch named by its backport hashtags. Failures, such as
+// conflicts, are reported as a comment on the merged change.
+func (b *BackportServiceImpl) ExecuteBackport(change *types.PendingSubmitInfo) error {
+	if !Config.Backport.Enabled {
+		return nil
+	}
+
+	changeID := strconv.Itoa(change.ChangeNumber)
+	for _, branch := range backportBranches(change) {
+		if branch == change.Branch {
+			continue
+		}
+		pick, err := GerritChanges.CherryPick(changeID, change.CurrentRevision, branch)
+		if err != nil {
+			log.Printf("cherry-pick of change %s onto %s: %v", changeID, branch, err)
+			review := &types.ReviewInput{Message: backportFailureMessage(branch, err), Tag: jarvisTag}
+			if err := GerritChanges.PostReview(changeID, change.CurrentRevision, review); err != nil {
+				return err
+			}
+			continue
+		}
+		log.Printf("cherry-picked change %s onto %s as change %d", changeID, branch, pick.ChangeNumber)
+	}
+	return nil
+}
+
+// backportBranches returns the branches named by the backport hashtags of a change.
+func backportBranches(change *types.PendingSubmitInfo) []string {
+	var branches []string
+	for _, tag := range change.Hashtags {
+		if !strings.HasPrefix(tag, Config.Backport.Prefix) {
+			continue
+		}
+		if branch := strings.TrimPrefix(tag, Config.Backport.Prefix); branch != "" {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// backportFailureMessage explains why a change could not be cherry-picked onto a branch.
+func backportFailureMessage(branch string, err error) string {
+	statusErr, ok := err.(*StatusError)
+	if !ok {
+		return fmt.Sprintf("Jarvis could not backport this change to %s: %v", branch, err)
+	}
+	if statusErr.StatusCode == http.StatusConflict {
+		return fmt.Sprintf("Jarvis could not backport this change to %s, it conflicts with the branch: %s",
+			branch, statusErr.Body)
+	}
+	return fmt.Sprintf("Jarvis could not backport this change to %s, Gerrit returned status %d: %s",
+		branch, statusErr.StatusCode, statusErr.Body)
+}
@@ -0,0 +1,55 @@
+package services_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestBackportServiceImpl_ExecuteBackport(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Backport.Enabled = true
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	var picked []string
+	var comments []string
+	services.GerritServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			if strings.HasSuffix(pathing, "/review") {
+				comments = append(comments, string(content))
+				return []byte(")]}'{}"), nil
+			}
+			picked = append(picked, string(content))
+			if strings.Contains(string(content), "stable/2022.2") {
+				return nil, &services.StatusError{Method: http.MethodPost, URL: pathing,
+					StatusCode: http.StatusConflict, Body: "merge conflict"}
+			}
+			return []byte(`)]}'{"id": "pick", "_number": 11}`), nil
+		},
+	}
+	change := &types.PendingSubmitInfo{
+		ChangeNumber:    10,
+		Branch:          "master",
+		CurrentRevision: "184ebe53805e102605d11f6b143486d15c23a09c",
+		Hashtags:        []string{"backport:stable/2023.1", "backport:stable/2022.2", "other", "backport:master"},
+	}
+
+	// Act
+	err := services.Backport.ExecuteBackport(change)
+
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	if len(picked) != 2 || !strings.Contains(picked[0], `"destination":"stable/2023.1"`) {
+		t.Errorf("expected cherry-picks onto both stable branches, got: %v", picked)
+	}
+	if len(comments) != 1 || !strings.Contains(comments[0], "stable/2022.2") ||
+		!strings.Contains(comments[0], "conflicts") {
+		t.Errorf("expected the conflict to be reported on the change, got: %v", comments)
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"encoding/base32"
+	"regexp"
+	"strings"
+)
+
+// Checker UUIDs are encoded as
+//
+//	jarvis:<prefix>.<repository>
+//
+// where <repository> is the repository name in lower case, unpadded base32, which has no '.', so the prefix is
+// everything up to the last '.' and may contain dashes and dots. Both prefix and repository can be decoded from the
+// UUID, and checkers of one repository only share a UUID if they share a prefix.
+//
+// Checkers registered before this encoding use the legacy format jarvis:<prefix>-<hex sha1 of repository>. Their
+// prefix can still be decoded, but their repository cannot.
+
+var (
+	repositoryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+	legacyCheckerUUID  = regexp.MustCompile(`^(.+)-[0-9a-f]{40}$`)
+)
+
+// EncodeCheckerUUID returns the UUID of the checker for the given prefix and repository.
+func EncodeCheckerUUID(prefix, repository string) string {
+	return checkerScheme + ":" + prefix + "." + strings.ToLower(repositoryEncoding.EncodeToString([]byte(repository)))
+}
+
+// DecodeCheckerUUID returns the prefix and repository encoded in a checker UUID. The repository of legacy UUIDs is
+// empty. It reports false if the UUID is not one of ours.
+func DecodeCheckerUUID(uuid string) (prefix, repository string, ok bool) {
+	id := strings.TrimPrefix(uuid, checkerScheme+":")
+	if id == uuid {
+		return "", "", false
+	}
+
+	if m := legacyCheckerUUID.FindStringSubmatch(id); m != nil {
+		return m[1], "", true
+	}
+
+	i := strings.LastIndex(id, ".")
+	if i <= 0 {
+		return "", "", false
+	}
+	repo, err := repositoryEncoding.DecodeString(strings.ToUpper(id[i+1:]))
+	if err != nil || len(repo) == 0 {
+		return "", "", false
+	}
+	return id[:i], string(repo), true
+}
+
+// IsLegacyCheckerUUID reports whether a checker UUID uses the legacy format.
+func IsLegacyCheckerUUID(uuid string) bool {
+	return legacyCheckerUUID.MatchString(strings.TrimPrefix(uuid, checkerScheme+":"))
+}
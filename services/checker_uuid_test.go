@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/services"
+)
+
+func TestEncodeCheckerUUID(t *testing.T) {
+	testData := []struct {
+		prefix string
+		repo   string
+	}{
+		{prefix: "lint", repo: "MyProject"},
+		{prefix: "unit-tests", repo: "airship/treasuremap"},
+		{prefix: "py3.8-unit", repo: "a-b.c"},
+	}
+
+	for _, test := range testData {
+		// Act
+		uuid := services.EncodeCheckerUUID(test.prefix, test.repo)
+		prefix, repo, ok := services.DecodeCheckerUUID(uuid)
+
+		// Assert
+		if !ok || prefix != test.prefix || repo != test.repo {
+			t.Errorf("%s: decoded to (%q, %q, %t), expected (%q, %q)", uuid, prefix, repo, ok, test.prefix, test.repo)
+		}
+		if services.IsLegacyCheckerUUID(uuid) {
+			t.Errorf("%s: expected not to be a legacy UUID", uuid)
+		}
+	}
+}
+
+func TestDecodeCheckerUUID(t *testing.T) {
+	testData := []struct {
+		uuid   string
+		prefix string
+		ok     bool
+		legacy bool
+	}{
+		{uuid: "jarvis:jarvispipeline-061bc62acb425af5bc8a4689221eed5781831ecc", prefix: "jarvispipeline", ok: true,
+			legacy: true},
+		{uuid: "jarvis:unit-tests-061bc62acb425af5bc8a4689221eed5781831ecc", prefix: "unit-tests", ok: true,
+			legacy: true},
+		{uuid: "other:lint.jvsw4ylknlmwk3a"},
+		{uuid: "jarvis:lint"},
+		{uuid: "jarvis:lint.not-base32"},
+		{uuid: "jarvis:.jvsw4ylknlmwk3a"},
+	}
+
+	for _, test := range testData {
+		// Act
+		prefix, _, ok := services.DecodeCheckerUUID(test.uuid)
+
+		// Assert
+		if ok != test.ok || prefix != test.prefix {
+			t.Errorf("%s: decoded to (%q, %t), expected (%q, %t)", test.uuid, prefix, ok, test.prefix, test.ok)
+		}
+		if services.IsLegacyCheckerUUID(test.uuid) != test.legacy {
+			t.Errorf("%s: expected legacy: %t", test.uuid, test.legacy)
+		}
+	}
+}
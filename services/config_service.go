@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+const (
+	defaultPageSize      = 100
+	defaultSubmitQuery   = "status:open"
+	defaultRevertTag     = "jarvis-revert"
+	defaultBackportTag   = "backport:"
+	defaultDriftInterval = "15m"
+
+	// SubmitModePipeline leaves submitting the change to the merge pipeline.
+	SubmitModePipeline = "pipeline"
+	// SubmitModeConnector has the connector submit the change once the merge pipeline succeeded.
+	SubmitModeConnector = "connector"
+)
+
+var (
+	// Config is the active connector configuration.
+	Config = DefaultConfig()
+)
+
+// DefaultConfig returns the configuration used when no config file is given.
+func DefaultConfig() *types.ConnectorConfig {
+	return &types.ConnectorConfig{
+		PageSize: defaultPageSize,
+		Submit: types.SubmitConfig{
+			Query: defaultSubmitQuery,
+			Mode:  SubmitModePipeline,
+		},
+		Revert: types.RevertConfig{
+			Hashtag: defaultRevertTag,
+		},
+		Backport: types.BackportConfig{
+			Prefix: defaultBackportTag,
+		},
+		Drift: types.DriftConfig{
+			Interval: defaultDriftInterval,
+		},
+	}
+}
+
+// LoadConfig reads a JSON config file, filling unset values with their defaults.
+func LoadConfig(file string) (*types.ConnectorConfig, error) {
+	config := DefaultConfig()
+	content, err := ioutil.ReadFile(file) //nolint
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, err
+	}
+
+	if config.PageSize <= 0 {
+		config.PageSize = defaultPageSize
+	}
+	if config.Submit.Query == "" {
+		config.Submit.Query = defaultSubmitQuery
+	}
+	if config.Revert.Hashtag == "" {
+		config.Revert.Hashtag = defaultRevertTag
+	}
+	if config.Backport.Prefix == "" {
+		config.Backport.Prefix = defaultBackportTag
+	}
+	if config.Drift.Interval == "" {
+		config.Drift.Interval = defaultDriftInterval
+	}
+	if config.Submit.Mode != SubmitModePipeline && config.Submit.Mode != SubmitModeConnector {
+		return nil, fmt.Errorf("unknown submit mode %q", config.Submit.Mode)
+	}
+	for i, p := range config.Submit.Projects {
+		if p.Project == "" || p.Query == "" {
+			return nil, fmt.Errorf("submit project %d must set project and query", i)
+		}
+	}
+	if err := ValidateFreezeWindows(config.Freezes); err != nil {
+		return nil, err
+	}
+	if _, err := matchAny(config.PostMerge.Branches, ""); err != nil {
+		return nil, fmt.Errorf("post-merge branches: %v", err)
+	}
+	if err := ValidatePeriodicJobs(config.Periodic); err != nil {
+		return nil, err
+	}
+	if err := ValidateCheckerSpecs(config.Checkers); err != nil {
+		return nil, err
+	}
+	if config.Drift.Enabled && len(config.Checkers) == 0 {
+		return nil, fmt.Errorf("drift detection needs configured checkers")
+	}
+	if interval, err := time.ParseDuration(config.Drift.Interval); err != nil || interval <= 0 {
+		return nil, fmt.Errorf("drift interval %q must be a positive duration", config.Drift.Interval)
+	}
+	return config, nil
+}
+
+// ValidateCheckerSpecs checks every configured checker, and that no checker is configured twice.
+func ValidateCheckerSpecs(specs []types.CheckerSpec) error {
+	seen := map[string]bool{}
+	for i, spec := range specs {
+		if err := ValidateCheckerSpec(spec); err != nil {
+			return fmt.Errorf("checker %d: %v", i, err)
+		}
+		key := spec.Repository + "\x00" + spec.Prefix
+		if seen[key] {
+			return fmt.Errorf("checker %q of repository %q is defined twice", spec.Prefix, spec.Repository)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ValidateCheckerSpec checks that a checker names its repository and prefix, and that the fields it sets are ones
+// Gerrit accepts.
+func ValidateCheckerSpec(spec types.CheckerSpec) error {
+	if spec.Repository == "" || spec.Prefix == "" {
+		return fmt.Errorf("checker must set repository and prefix")
+	}
+	if strings.ContainsAny(spec.Prefix, ":/") {
+		return fmt.Errorf("checker prefix %q must not contain ':' or '/'", spec.Prefix)
+	}
+	if spec.Status != "" && spec.Status != CheckerEnabledString && spec.Status != CheckerDisabledString {
+		return fmt.Errorf("unknown checker status %q", spec.Status)
+	}
+	for _, condition := range spec.Blocking {
+		if condition != BlockingStateNotPassing {
+			return fmt.Errorf("unknown blocking condition %q, expected %s", condition, BlockingStateNotPassing)
+		}
+	}
+	if spec.URL != "" {
+		u, err := url.Parse(spec.URL)
+		if err != nil {
+			return fmt.Errorf("checker url: %v", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("checker url %q must be an absolute http(s) URL", spec.URL)
+		}
+	}
+	return nil
+}
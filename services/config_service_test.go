@@ -0,0 +1,119 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestLoadConfig(t *testing.T) {
+	// Arrange
+	file, err := ioutil.TempFile("", "jarvis-config")
+	if err != nil {
+		t.Fatalf("Received error setting up TestLoadConfig function: %v", err)
+	}
+	defer os.Remove(file.Name())
+	content := `{"submit": {"projects": [{"project": "^airship/.*", "query": "status:open label:Code-Review=+2"}]}}`
+	if _, err := file.WriteString(content); err != nil {
+		t.Fatalf("Received error setting up TestLoadConfig function: %v", err)
+	}
+	file.Close()
+
+	// Act
+	config, err := services.LoadConfig(file.Name())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	if config.PageSize != 100 {
+		t.Errorf("expected default page size 100, got: %d", config.PageSize)
+	}
+	if config.Submit.Query != "status:open" {
+		t.Errorf("expected default submit query, got: %q", config.Submit.Query)
+	}
+	if len(config.Submit.Projects) != 1 || config.Submit.Projects[0].Project != "^airship/.*" {
+		t.Errorf("unexpected project queries: %v", config.Submit.Projects)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	testData := []struct {
+		name    string
+		content string
+	}{
+		{name: "project without query", content: `{"submit": {"projects": [{"project": "^airship/.*"}]}}`},
+		{name: "query without project", content: `{"submit": {"projects": [{"query": "status:open"}]}}`},
+		{name: "drift without checkers", content: `{"drift": {"enabled": true}}`},
+	}
+
+	for _, test := range testData {
+		// Arrange
+		file, err := ioutil.TempFile("", "jarvis-config")
+		if err != nil {
+			t.Fatalf("Received error setting up TestLoadConfigInvalid function: %v", err)
+		}
+		defer os.Remove(file.Name())
+		if _, err := file.WriteString(test.content); err != nil {
+			t.Fatalf("Received error setting up TestLoadConfigInvalid function: %v", err)
+		}
+		file.Close()
+
+		// Act
+		_, err = services.LoadConfig(file.Name())
+
+		// Assert
+		if err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+func TestValidateCheckerSpecs(t *testing.T) {
+	testData := []struct {
+		name  string
+		specs []types.CheckerSpec
+		valid bool
+	}{
+		{name: "valid", specs: []types.CheckerSpec{{Repository: "a", Prefix: "lint"}, {Repository: "b", Prefix: "lint"}},
+			valid: true},
+		{name: "missing prefix", specs: []types.CheckerSpec{{Repository: "a"}}},
+		{name: "duplicate", specs: []types.CheckerSpec{{Repository: "a", Prefix: "lint"}, {Repository: "a", Prefix: "lint"}}},
+		{name: "unknown status", specs: []types.CheckerSpec{{Repository: "a", Prefix: "lint", Status: "ON"}}},
+	}
+
+	for _, test := range testData {
+		// Act
+		err := services.ValidateCheckerSpecs(test.specs)
+
+		// Assert
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid: %t, got error: %v", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestLoadConfigInvalidDriftInterval(t *testing.T) {
+	// Arrange
+	file, err := ioutil.TempFile("", "jarvis-config")
+	if err != nil {
+		t.Fatalf("Received error setting up TestLoadConfigInvalidDriftInterval function: %v", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(`{"checkers": [{"repository": "a", "prefix": "lint"}],
+		"drift": {"enabled": true, "interval": "often"}}`); err != nil {
+		t.Fatalf("Received error setting up TestLoadConfigInvalidDriftInterval function: %v", err)
+	}
+	file.Close()
+
+	// Act
+	_, err = services.LoadConfig(file.Name())
+
+	// Assert
+	if err == nil {
+		t.Errorf("expected an error for an invalid drift interval")
+	}
+}
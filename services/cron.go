@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five field cron expression: minute, hour, day of month, month and day of week. Each
+// field accepts "*", numbers, ranges ("1-5"), lists ("1,3") and steps ("*/15", "0-30/10").
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// Like cron, a day matches either day field when both are restricted.
+	domStar, dowStar bool
+}
+
+// cronFields holds the bounds of each field of a cron expression.
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+// ParseCron parses a five field cron expression.
+func ParseCron(spec string) (*CronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have %d fields", spec, len(cronFields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		var err error
+		if bits[i], err = parseCronField(field, cronFields[i].min, cronFields[i].max); err != nil {
+			return nil, fmt.Errorf("cron expression %q: %s: %v", spec, cronFields[i].name, err)
+		}
+	}
+
+	return &CronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField returns the set of values matched by a single field as a bit set.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			part = part[:i]
+		}
+
+		low, high := min, max
+		if part != "*" {
+			var err error
+			bounds := strings.SplitN(part, "-", 2)
+			if low, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid range %q", part)
+				}
+			}
+		}
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Matches reports whether the schedule fires in the minute of t.
+func (c *CronSchedule) Matches(t time.Time) bool {
+	return c.minute&(1<<uint(t.Minute())) != 0 && c.hour&(1<<uint(t.Hour())) != 0 &&
+		c.month&(1<<uint(t.Month())) != 0 && c.dayMatches(t)
+}
+
+// dayMatches reports whether the day fields match the day of t.
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// ActiveSince returns the most recent time the schedule fired within the given duration before t, and whether it
+// fired at all. Months, days and hours that do not match are skipped as a whole, so long durations stay cheap.
+func (c *CronSchedule) ActiveSince(t time.Time, d time.Duration) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+	limit := t.Add(-d)
+	for !t.Before(limit) {
+		year, month, day := t.Date()
+		loc := t.Location()
+		switch {
+		case c.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month, 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case !c.dayMatches(t):
+			t = time.Date(year, month, day, 0, 0, 0, 0, loc).Add(-time.Minute)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(year, month, day, t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+		default:
+			// The latest minute of this hour, up to t, the schedule fires in.
+			minutes := c.minute & (1<<uint(t.Minute()+1) - 1)
+			if minutes == 0 {
+				t = time.Date(year, month, day, t.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+				continue
+			}
+			since := time.Date(year, month, day, t.Hour(), bits.Len64(minutes)-1, 0, 0, loc)
+			if since.Before(limit) {
+				return time.Time{}, false
+			}
+			return since, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,93 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/services"
+)
+
+func TestParseCron(t *testing.T) {
+	testData := []struct {
+		spec    string
+		time    string
+		matches bool
+	}{
+		{spec: "* * * * *", time: "2023-03-10T12:34:00Z", matches: true},
+		{spec: "30 18 * * 5", time: "2023-03-10T18:30:00Z", matches: true},
+		{spec: "30 18 * * 5", time: "2023-03-11T18:30:00Z", matches: false},
+		{spec: "*/15 9-17 * * 1-5", time: "2023-03-10T09:45:00Z", matches: true},
+		{spec: "*/15 9-17 * * 1-5", time: "2023-03-10T09:40:00Z", matches: false},
+		{spec: "0 0 1,15 * *", time: "2023-03-15T00:00:00Z", matches: true},
+		// Both day fields restricted: either one matching is enough.
+		{spec: "0 0 1 * 0", time: "2023-03-12T00:00:00Z", matches: true},
+	}
+
+	for _, test := range testData {
+		schedule, err := services.ParseCron(test.spec)
+		if err != nil {
+			t.Errorf("ParseCron(%q) returned error: %v", test.spec, err)
+			continue
+		}
+		at, _ := time.Parse(time.RFC3339, test.time)
+		if schedule.Matches(at) != test.matches {
+			t.Errorf("%q matching %s: expected %t", test.spec, test.time, test.matches)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		if _, err := services.ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) expected to return an error", spec)
+		}
+	}
+}
+
+func TestCronSchedule_ActiveSince(t *testing.T) {
+	// Arrange
+	schedule, _ := services.ParseCron("0 18 * * 5")
+	friday, _ := time.Parse(time.RFC3339, "2023-03-10T18:00:00Z")
+
+	// Act
+	since, active := schedule.ActiveSince(friday.Add(47*time.Hour), 48*time.Hour)
+	_, expired := schedule.ActiveSince(friday.Add(49*time.Hour), 48*time.Hour)
+
+	// Assert
+	if !active || !since.Equal(friday) {
+		t.Errorf("expected schedule to be active since %s, got: %s %t", friday, since, active)
+	}
+	if expired {
+		t.Errorf("expected schedule to no longer be active after its duration")
+	}
+}
+
+func TestCronSchedule_ActiveSinceMatchesEveryMinute(t *testing.T) {
+	specs := []string{"0 18 * * 5", "*/15 9-17 * * 1-5", "30 2 1 * *", "0 0 1 1 *", "0 0 1 * 0", "59 23 31 12 *"}
+	start, _ := time.Parse(time.RFC3339, "2023-03-10T12:34:00Z")
+
+	for _, spec := range specs {
+		// Arrange
+		schedule, err := services.ParseCron(spec)
+		if err != nil {
+			t.Fatalf("Received error setting up TestCronSchedule_ActiveSinceMatchesEveryMinute function: %v", err)
+		}
+		for _, d := range []time.Duration{time.Hour, 48 * time.Hour, 40 * 24 * time.Hour, 400 * 24 * time.Hour} {
+			expected, expectedActive := time.Time{}, false
+			for since := start; !since.Before(start.Add(-d)); since = since.Add(-time.Minute) {
+				if schedule.Matches(since) {
+					expected, expectedActive = since, true
+					break
+				}
+			}
+
+			// Act
+			since, active := schedule.ActiveSince(start, d)
+
+			// Assert
+			if active != expectedActive || !since.Equal(expected) {
+				t.Errorf("%q within %s: expected %s %t, got: %s %t", spec, d, expected, expectedActive, since, active)
+			}
+		}
+	}
+}
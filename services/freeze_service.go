@@ -0,0 +1,239 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+const (
+	// notifiedRetention is how long a change is remembered as told about a freeze window. Once forgotten, the
+	// messages of the change tell whether it was.
+	notifiedRetention = 24 * time.Hour
+)
+
+var (
+	Freeze freezeService = &FreezeServiceImpl{notified: map[string]time.Time{}}
+)
+
+type freezeService interface {
+	ActiveFreeze(change *types.PendingSubmitInfo, now time.Time) (*types.FreezeWindow, error)
+	NotifyFrozen(change *types.PendingSubmitInfo, window *types.FreezeWindow) error
+}
+
+type FreezeServiceImpl struct {
+	mu sync.Mutex
+	// windows are the parsed windows of source, the Config.Freezes they were parsed from.
+	windows []*freezeWindow
+	source  []types.FreezeWindow
+	// notified holds when changes were told about a freeze window, keyed by change and window.
+	notified map[string]time.Time
+}
+
+// freezeWindow is a freeze window with its schedule, times and patterns parsed.
+type freezeWindow struct {
+	window     *types.FreezeWindow
+	schedule   *CronSchedule
+	duration   time.Duration
+	start, end time.Time
+	projects   []*regexp.Regexp
+	branches   []*regexp.Regexp
+}
+
+// ValidateFreezeWindows checks that every freeze window is well formed.
+func ValidateFreezeWindows(windows []types.FreezeWindow) error {
+	_, err := parseFreezeWindows(windows)
+	return err
+}
+
+// ActiveFreeze returns the first configured freeze window that currently applies to the change, or nil.
+func (f *FreezeServiceImpl) ActiveFreeze(change *types.PendingSubmitInfo, now time.Time) (*types.FreezeWindow, error) {
+	windows, err := f.parsedWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, w := range windows {
+		if matchHashtag(change, w.window.ExemptHashtags) != "" {
+			continue
+		}
+		if w.appliesTo(change) && w.activeAt(now) {
+			return w.window, nil
+		}
+	}
+	return nil, nil
+}
+
+// parsedWindows returns the parsed Config.Freezes. They are only parsed again once Config.Freezes is replaced, e.g.
+// by loading another config.
+func (f *FreezeServiceImpl) parsedWindows() ([]*freezeWindow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(Config.Freezes) == 0 {
+		return nil, nil
+	}
+	if len(f.source) == len(Config.Freezes) && &f.source[0] == &Config.Freezes[0] {
+		return f.windows, nil
+	}
+
+	windows, err := parseFreezeWindows(Config.Freezes)
+	if err != nil {
+		return nil, err
+	}
+	f.windows, f.source = windows, Config.Freezes
+	return windows, nil
+}
+
+// NotifyFrozen posts a comment explaining why the change is not submitted, once per change and freeze window.
+func (f *FreezeServiceImpl) NotifyFrozen(change *types.PendingSubmitInfo, window *types.FreezeWindow) error {
+	key := change.ID + "/" + window.Name
+	now := time.Now()
+	f.mu.Lock()
+	for k, at := range f.notified {
+		if now.Sub(at) > notifiedRetention {
+			delete(f.notified, k)
+		}
+	}
+	_, notified := f.notified[key]
+	f.mu.Unlock()
+	if notified {
+		return nil
+	}
+
+	changeID := strconv.Itoa(change.ChangeNumber)
+	msg := fmt.Sprintf("Jarvis will not submit this change while the %q merge freeze is in effect.", window.Name)
+	posted, err := hasJarvisMessage(changeID, msg)
+	if err != nil {
+		return err
+	}
+	if !posted {
+		review := &types.ReviewInput{Message: msg, Tag: jarvisTag}
+		if window.Message != "" {
+			review.Message += "\n\n" + window.Message
+		}
+		revision := strconv.Itoa(change.Revisions[change.CurrentRevision].Number)
+		if err := GerritChanges.PostReview(changeID, revision, review); err != nil {
+			return err
+		}
+	}
+
+	f.mu.Lock()
+	f.notified[key] = now
+	f.mu.Unlock()
+	return nil
+}
+
+// hasJarvisMessage reports whether the connector already posted a message containing text on the change.
+func hasJarvisMessage(changeID, text string) (bool, error) {
+	change, err := GerritChanges.GetChange(changeID, "MESSAGES")
+	if err != nil {
+		return false, err
+	}
+	for _, message := range change.Messages {
+		if message.Tag == jarvisTag && strings.Contains(message.Message, text) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseFreezeWindows parses every freeze window.
+func parseFreezeWindows(windows []types.FreezeWindow) ([]*freezeWindow, error) {
+	parsed := make([]*freezeWindow, 0, len(windows))
+	for i := range windows {
+		window := &windows[i]
+		if window.Name == "" {
+			return nil, fmt.Errorf("freeze window %d has no name", i)
+		}
+		w, err := parseFreezeWindow(window)
+		if err != nil {
+			return nil, fmt.Errorf("freeze window %q: %v", window.Name, err)
+		}
+		parsed = append(parsed, w)
+	}
+	return parsed, nil
+}
+
+// parseFreezeWindow parses the schedule or the times of a window, and its patterns.
+func parseFreezeWindow(window *types.FreezeWindow) (*freezeWindow, error) {
+	w := &freezeWindow{window: window}
+	var err error
+	if w.projects, err = compilePatterns(window.Projects); err != nil {
+		return nil, err
+	}
+	if w.branches, err = compilePatterns(window.Branches); err != nil {
+		return nil, err
+	}
+
+	if window.Schedule != "" {
+		if w.schedule, err = ParseCron(window.Schedule); err != nil {
+			return nil, err
+		}
+		if w.duration, err = time.ParseDuration(window.Duration); err != nil {
+			return nil, fmt.Errorf("invalid duration: %v", err)
+		}
+		return w, nil
+	}
+
+	if w.start, err = time.Parse(time.RFC3339, window.Start); err != nil {
+		return nil, fmt.Errorf("invalid start: %v", err)
+	}
+	if w.end, err = time.Parse(time.RFC3339, window.End); err != nil {
+		return nil, fmt.Errorf("invalid end: %v", err)
+	}
+	return w, nil
+}
+
+// appliesTo reports whether the window covers the project and branch of the change.
+func (w *freezeWindow) appliesTo(change *types.PendingSubmitInfo) bool {
+	return matchPatterns(w.projects, change.Project) && matchPatterns(w.branches, change.Branch)
+}
+
+// activeAt reports whether the window is active at the given time.
+func (w *freezeWindow) activeAt(now time.Time) bool {
+	if w.schedule != nil {
+		_, active := w.schedule.ActiveSince(now, w.duration)
+		return active
+	}
+	return !now.Before(w.start) && now.Before(w.end)
+}
+
+// matchAny reports whether the value matches any of the regular expressions, or true if there are none.
+func matchAny(patterns []string, value string) (bool, error) {
+	compiled, err := compilePatterns(patterns)
+	if err != nil {
+		return false, err
+	}
+	return matchPatterns(compiled, value), nil
+}
+
+// compilePatterns compiles regular expressions that must match a whole value.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchPatterns reports whether the value matches any of the compiled patterns, or true if there are none.
+func matchPatterns(patterns []*regexp.Regexp, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, re := range patterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestFreezeServiceImpl_ActiveFreeze(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Freezes = []types.FreezeWindow{{
+		Name:           "release",
+		Projects:       []string{"airship/.*"},
+		Branches:       []string{"master"},
+		Start:          "2023-03-01T00:00:00Z",
+		End:            "2023-03-08T00:00:00Z",
+		ExemptHashtags: []string{"hotfix"},
+	}}
+	defer func() { services.Config = services.DefaultConfig() }()
+	during, _ := time.Parse(time.RFC3339, "2023-03-05T00:00:00Z")
+	after, _ := time.Parse(time.RFC3339, "2023-03-09T00:00:00Z")
+
+	testData := []struct {
+		name   string
+		change types.PendingSubmitInfo
+		now    time.Time
+		frozen bool
+	}{
+		{name: "frozen", change: types.PendingSubmitInfo{
+			Project: "airship/treasuremap", Branch: "master"}, now: during, frozen: true},
+		{name: "after", change: types.PendingSubmitInfo{Project: "airship/treasuremap", Branch: "master"}, now: after},
+		{name: "project", change: types.PendingSubmitInfo{Project: "other/airship", Branch: "master"}, now: during},
+		{name: "branch", change: types.PendingSubmitInfo{Project: "airship/treasuremap", Branch: "stable"}, now: during},
+		{name: "exempt", change: types.PendingSubmitInfo{
+			Project: "airship/treasuremap", Branch: "master", Hashtags: []string{"hotfix"}}, now: during},
+	}
+
+	for _, test := range testData {
+		// Act
+		window, err := services.Freeze.ActiveFreeze(&test.change, test.now)
+
+		// Assert
+		if err != nil {
+			t.Errorf("%s: resulting error expected to be nil, received: %v", test.name, err)
+		}
+		if (window != nil) != test.frozen {
+			t.Errorf("%s: expected frozen to be %t, got window: %v", test.name, test.frozen, window)
+		}
+	}
+}
+
+func TestFreezeServiceImpl_NotifyFrozen(t *testing.T) {
+	testData := []struct {
+		name     string
+		id       string
+		messages string
+		expected int
+	}{
+		{name: "not notified", id: "notify-frozen", messages: `[]`, expected: 1},
+		// A comment posted before a restart is found in the messages of the change.
+		{name: "notified before", id: "notified-frozen", messages: `[{"tag": "autogenerated:jarvis",
+			"message": "Patch Set 1:\n\nJarvis will not submit this change while the \"release\" merge freeze is in effect."}]`},
+		{name: "other message", id: "other-frozen", messages: `[{"tag": "autogenerated:jarvis",
+			"message": "Jarvis merge pipeline failed"}]`, expected: 1},
+	}
+
+	for _, test := range testData {
+		// Arrange
+		posts, gets := 0, 0
+		messages := test.messages
+		services.GerritServer = serverServiceMock{
+			getURLFn: func() url.URL {
+				return url.URL{}
+			},
+			getFn: func(u *url.URL) ([]byte, error) {
+				gets++
+				return []byte(`)]}'{"messages": ` + messages + `}`), nil
+			},
+			postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+				posts++
+				return []byte(")]}'{}"), nil
+			},
+		}
+		change := &types.PendingSubmitInfo{ID: test.id, ChangeNumber: 1}
+		window := &types.FreezeWindow{Name: "release"}
+
+		// Act
+		for i := 0; i < 3; i++ {
+			if err := services.Freeze.NotifyFrozen(change, window); err != nil {
+				t.Errorf("%s: resulting error expected to be nil, received: %v", test.name, err)
+			}
+		}
+
+		// Assert
+		if posts != test.expected {
+			t.Errorf("%s: expected %d comments, got: %d", test.name, test.expected, posts)
+		}
+		if gets != 1 {
+			t.Errorf("%s: expected the messages to be read once, got: %d", test.name, gets)
+		}
+	}
+}
+
+func TestValidateFreezeWindows(t *testing.T) {
+	invalid := []types.FreezeWindow{
+		{Start: "2023-03-01T00:00:00Z", End: "2023-03-08T00:00:00Z"},
+		{Name: "no range"},
+		{Name: "bad cron", Schedule: "* *", Duration: "1h"},
+		{Name: "bad duration", Schedule: "0 18 * * 5", Duration: "two days"},
+		{Name: "bad regexp", Projects: []string{"("}, Start: "2023-03-01T00:00:00Z", End: "2023-03-08T00:00:00Z"},
+	}
+	for _, window := range invalid {
+		if err := services.ValidateFreezeWindows([]types.FreezeWindow{window}); err == nil {
+			t.Errorf("expected freeze window %+v to be invalid", window)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+// jarvisTag marks the messages posted by the connector as automated.
+const (
+	jarvisTag = "autogenerated:jarvis"
+)
+
+var (
+	GerritChanges gerritChangeService = &GerritChangeServiceImpl{}
+)
+
+type gerritChangeService interface {
+	QueryChanges(query string, options ...string) ([]*types.PendingSubmitInfo, error)
+	GetChange(changeID string, options ...string) (*types.PendingSubmitInfo, error)
+	PostReview(changeID, revision string, input *types.ReviewInput) error
+	SubmitRevision(changeID, revision string) error
+	RelatedChanges(changeID, revision string) ([]types.RelatedChangeAndCommitInfo, error)
+	GetBranch(project, branch string) (*types.BranchInfo, error)
+	GetCommit(project, commit string) (*types.CommitInfo, error)
+	ListProjects() ([]*types.ProjectInfo, error)
+	Rebase(changeID string) error
+	GetChecks(changeID, revision string) ([]*types.CheckInfo, error)
+	RevisionFiles(changeID, revision string) (map[string]types.FileInfo, error)
+	Revert(changeID, message string) (*types.PendingSubmitInfo, error)
+	AddReviewer(changeID, reviewer string) error
+	AddHashtags(changeID string, hashtags ...string) error
+	CherryPick(changeID, revision, destination string) (*types.PendingSubmitInfo, error)
+}
+
+type GerritChangeServiceImpl struct{}
+
+// QueryChanges returns every change matching the query, following Gerrit's
+// _more_changes marker until all pages have been fetched.
+func (g *GerritChangeServiceImpl) QueryChanges(query string, options ...string) ([]*types.PendingSubmitInfo, error) {
+	var changes []*types.PendingSubmitInfo
+	for {
+		page, err := g.queryPage(query, len(changes), options)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, page...)
+
+		if len(page) == 0 || !page[len(page)-1].MoreChanges {
+			return changes, nil
+		}
+	}
+}
+
+// queryPage fetches a single page of query results, starting at the given offset.
+func (g *GerritChangeServiceImpl) queryPage(
+	query string, start int, options []string) ([]*types.PendingSubmitInfo, error) {
+	u := GerritServer.GetURL()
+
+	u.Path = path.Join(u.Path, "a/changes/") + "/"
+	q := u.Query()
+
+	for _, o := range options {
+		q.Add("o", o)
+	}
+	q.Add("q", query)
+	q.Add("n", strconv.Itoa(Config.PageSize))
+	if start > 0 {
+		q.Add("S", strconv.Itoa(start))
+	}
+	u.RawQuery = q.Encode()
+
+	content, err := GerritServer.Get(&u)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*types.PendingSubmitInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// GetChange returns a single change, with the details selected by the options.
+func (g *GerritChangeServiceImpl) GetChange(changeID string, options ...string) (*types.PendingSubmitInfo, error) {
+	u := GerritServer.GetURL()
+	u.Path = path.Join(u.Path, "a/changes", changeID)
+	q := u.Query()
+	for _, o := range options {
+		q.Add("o", o)
+	}
+	u.RawQuery = q.Encode()
+
+	content, err := GerritServer.Get(&u)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.PendingSubmitInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// PostReview posts a review, i.e. a message and/or label votes, onto a revision of a change.
+func (g *GerritChangeServiceImpl) PostReview(changeID, revision string, input *types.ReviewInput) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	pathing := fmt.Sprintf("a/changes/%s/revisions/%s/review", changeID, revision)
+	_, err = GerritServer.PostPath(pathing, jsonHeaders(), body)
+	return err
+}
+
+// SubmitRevision submits a change, failing unless the given revision is still the current one.
+func (g *GerritChangeServiceImpl) SubmitRevision(changeID, revision string) error {
+	pathing := fmt.Sprintf("a/changes/%s/revisions/%s/submit", changeID, revision)
+	_, err := GerritServer.PostPath(pathing, jsonHeaders(), []byte("{}"))
+	return err
+}
+
+// RelatedChanges returns the relation chain of a revision, descendants first and ancestors last.
+func (g *GerritChangeServiceImpl) RelatedChanges(
+	changeID, revision string) ([]types.RelatedChangeAndCommitInfo, error) {
+	u := GerritServer.GetURL()
+	u.Path = path.Join(u.Path, fmt.Sprintf("a/changes/%s/revisions/%s/related", changeID, revision))
+
+	content, err := GerritServer.Get(&u)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.RelatedChangesInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Changes, nil
+}
+
+// GetBranch returns a branch of a project, including the commit it points to.
+func (g *GerritChangeServiceImpl) GetBranch(project, branch string) (*types.BranchInfo, error) {
+	u := restURL("a", "projects", project, "branches", branch)
+	content, err := GerritServer.Get(&u)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.BranchInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetCommit returns a commit of a project, including its parents.
+func (g *GerritChangeServiceImpl) GetCommit(project, commit string) (*types.CommitInfo, error) {
+	u := restURL("a", "projects", project, "commits", commit)
+	content, err := GerritServer.Get(&u)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.CommitInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// Rebase rebases the current revision of a change onto the tip of its target branch, creating a new patchset.
+func (g *GerritChangeServiceImpl) Rebase(changeID string) error {
+	_, err := GerritServer.PostPath(fmt.Sprintf("a/changes/%s/rebase", changeID), jsonHeaders(), []byte("{}"))
+	return err
+}
+
+// GetChecks returns the checks of a revision.
+func (g *GerritChangeServiceImpl) GetChecks(changeID, revision string) ([]*types.CheckInfo, error) {
+	pathing := fmt.Sprintf("a/changes/%s/revisions/%s/checks", changeID, revision)
+	content, err := GerritServer.GetPath(pathing, jsonHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*types.CheckInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// RevisionFiles returns the files a revision modifies, keyed by their path.
+func (g *GerritChangeServiceImpl) RevisionFiles(changeID, revision string) (map[string]types.FileInfo, error) {
+	pathing := fmt.Sprintf("a/changes/%s/revisions/%s/files", changeID, revision)
+	content, err := GerritServer.GetPath(pathing, jsonHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	var out map[string]types.FileInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Revert creates a change reverting a merged change.
+func (g *GerritChangeServiceImpl) Revert(changeID, message string) (*types.PendingSubmitInfo, error) {
+	body, err := json.Marshal(&types.RevertInput{Message: message})
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := GerritServer.PostPath(fmt.Sprintf("a/changes/%s/revert", changeID), jsonHeaders(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.PendingSubmitInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// AddReviewer adds an account, given by ID, username or email, as reviewer of a change.
+func (g *GerritChangeServiceImpl) AddReviewer(changeID, reviewer string) error {
+	body, err := json.Marshal(&types.ReviewerInput{Reviewer: reviewer})
+	if err != nil {
+		return err
+	}
+
+	_, err = GerritServer.PostPath(fmt.Sprintf("a/changes/%s/reviewers", changeID), jsonHeaders(), body)
+	return err
+}
+
+// AddHashtags sets hashtags on a change.
+func (g *GerritChangeServiceImpl) AddHashtags(changeID string, hashtags ...string) error {
+	body, err := json.Marshal(&types.HashtagsInput{Add: hashtags})
+	if err != nil {
+		return err
+	}
+
+	_, err = GerritServer.PostPath(fmt.Sprintf("a/changes/%s/hashtags", changeID), jsonHeaders(), body)
+	return err
+}
+
+// CherryPick creates a change cherry-picking a revision onto the destination branch, keeping its commit message.
+func (g *GerritChangeServiceImpl) CherryPick(changeID, revision, destination string) (*types.PendingSubmitInfo, error) {
+	body, err := json.Marshal(&types.CherryPickInput{Destination: destination})
+	if err != nil {
+		return nil, err
+	}
+
+	pathing := fmt.Sprintf("a/changes/%s/revisions/%s/cherrypick", changeID, revision)
+	content, err := GerritServer.PostPath(pathing, jsonHeaders(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var out types.PendingSubmitInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListProjects returns the projects visible to us, with their parent project, ordered by name.
+func (g *GerritChangeServiceImpl) ListProjects() ([]*types.ProjectInfo, error) {
+	u := restURL("a", "projects")
+	u.Path += "/"
+	u.RawPath += "/"
+	u.RawQuery = "t"
+	content, err := GerritServer.Get(&u)
+	if err != nil {
+		return nil, err
+	}
+
+	var out map[string]*types.ProjectInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	projects := make([]*types.ProjectInfo, 0, len(out))
+	for name, project := range out {
+		project.Name = name
+		projects = append(projects, project)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+	return projects, nil
+}
+
+// restURL returns the URL of a REST endpoint from its path segments. Each segment is escaped on its own, so project
+// and branch names containing slashes can be used as a single segment.
+func restURL(segments ...string) url.URL {
+	u := GerritServer.GetURL()
+	rawPath := u.EscapedPath()
+	for _, segment := range segments {
+		u.Path = path.Join(u.Path, segment)
+		rawPath = path.Join(rawPath, url.PathEscape(segment))
+	}
+	u.RawPath = rawPath
+	return u
+}
+
+// tektonAccount converts a Gerrit account for use in a pipeline payload.
+func tektonAccount(account types.AccountInfo) types.TektonAccount {
+	return types.TektonAccount{
+		AccountID: account.AccountID,
+		Name:      account.Name,
+		Email:     account.Email,
+		Username:  account.Username,
+	}
+}
+
+// jsonHeaders returns the headers for a request with a JSON body.
+func jsonHeaders() []types.Header {
+	return []types.Header{{
+		Key:   "Content-Type",
+		Value: "application/json",
+	}}
+}
@@ -0,0 +1,49 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestGerritChangeServiceImpl_QueryChanges(t *testing.T) {
+	// Arrange
+	var starts []string
+	serverMock := serverServiceMock{
+		getURLFn: func() url.URL {
+			u, _ := url.Parse("https://website.com")
+			return *u
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			start := u.Query().Get("S")
+			starts = append(starts, start)
+			offset, _ := strconv.Atoi(start)
+
+			// Three pages of two changes, the last page has no _more_changes marker.
+			out := []*types.PendingSubmitInfo{{ChangeNumber: offset + 1}, {ChangeNumber: offset + 2}}
+			out[1].MoreChanges = offset < 4
+			body, err := json.Marshal(&out)
+			body = append([]byte(")]}'"), body...)
+			return body, err
+		},
+	}
+	services.GerritServer = serverMock
+
+	// Act
+	result, err := services.GerritChanges.QueryChanges("status:open", "LABELS")
+
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	if len(result) != 6 {
+		t.Errorf("expected 6 changes, got: %d", len(result))
+	}
+	if len(starts) != 3 || starts[0] != "" || starts[1] != "2" || starts[2] != "4" {
+		t.Errorf("unexpected page offsets requested: %v", starts)
+	}
+}
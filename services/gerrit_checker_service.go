@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -121,14 +122,10 @@ func (g *GerritCheckerServiceImpl) ExecuteCheck(pc *types.PendingChecksInfo) err
 	return nil
 }
 
-// checkerPrefix extracts the prefix to check for from a checker UUID.
+// CheckerPrefix extracts the prefix to check for from a checker UUID.
 func (g *GerritCheckerServiceImpl) CheckerPrefix(uuid string) (string, bool) {
-	uuid = strings.TrimPrefix(uuid, checkerScheme+":")
-	fields := strings.Split(uuid, "-")
-	if len(fields) != 2 {
-		return "", false
-	}
-	return fields[0], true
+	prefix, _, ok := DecodeCheckerUUID(uuid)
+	return prefix, ok
 }
 
 // PostCheck posts a single check result onto a change.
@@ -167,12 +164,9 @@ func (g *GerritCheckerServiceImpl) checkChange(uuid string, repository string, c
 		Key:   "X-Jarvis",
 		Value: "create",
 	}}
-	data := types.TektonListenerPayload{
-		RepoRoot:       GerritServer.GetRepoRoot(),
-		Project:        repository,
-		ChangeNumber:   changeID,
-		PatchSetNumber: psID,
-		CheckerUUID:    uuid,
+	data, err := g.listenerPayload(uuid, repository, changeID, psID)
+	if err != nil {
+		return nil, "", err
 	}
 	body, err := json.Marshal(data)
 	if err != nil {
@@ -191,3 +185,60 @@ func (g *GerritCheckerServiceImpl) checkChange(uuid string, repository string, c
 	var details = ""
 	return messages, details, nil
 }
+
+// listenerPayload describes the patchset to check, with the details of the change pipelines commonly need. Only
+// the files of the checked patchset are fetched.
+func (g *GerritCheckerServiceImpl) listenerPayload(
+	uuid string, repository string, changeID string, psID int) (*types.TektonListenerPayload, error) {
+	change, err := GerritChanges.GetChange(changeID,
+		"CURRENT_REVISION", "CURRENT_COMMIT", "CURRENT_FILES", "DETAILED_ACCOUNTS")
+	if err != nil {
+		return nil, err
+	}
+	sha, revision := change.CurrentRevision, change.Revisions[change.CurrentRevision]
+	if revision.Number != psID {
+		// The patchset is no longer the current one.
+		if sha, revision, err = g.patchSetRevision(changeID, psID); err != nil {
+			return nil, err
+		}
+	}
+
+	data := &types.TektonListenerPayload{
+		Version:        types.CheckPayloadVersion,
+		RepoRoot:       GerritServer.GetRepoRoot(),
+		Project:        repository,
+		Branch:         change.Branch,
+		ChangeNumber:   changeID,
+		PatchSetNumber: psID,
+		Revision:       sha,
+		Ref:            revision.Ref,
+		Owner:          tektonAccount(change.Owner),
+		Subject:        revision.Commit.Subject,
+		Files:          []string{},
+		CheckerUUID:    uuid,
+	}
+	for file := range revision.Files {
+		// Skip magic files like /COMMIT_MSG.
+		if !strings.HasPrefix(file, "/") {
+			data.Files = append(data.Files, file)
+		}
+	}
+	sort.Strings(data.Files)
+
+	return data, nil
+}
+
+// patchSetRevision returns the commit SHA-1 and the revision, with its commit and files, of a patchset.
+func (g *GerritCheckerServiceImpl) patchSetRevision(changeID string, psID int) (string, types.Revision, error) {
+	change, err := GerritChanges.GetChange(changeID, "ALL_REVISIONS", "ALL_COMMITS")
+	if err != nil {
+		return "", types.Revision{}, err
+	}
+	for sha, revision := range change.Revisions {
+		if revision.Number == psID {
+			revision.Files, err = GerritChanges.RevisionFiles(changeID, sha)
+			return sha, revision, err
+		}
+	}
+	return "", types.Revision{}, fmt.Errorf("change %s has no patchset %d", changeID, psID)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/att-comdev/jarvis-connector/services"
 	"github.com/att-comdev/jarvis-connector/types"
 	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -64,6 +65,7 @@ func TestGerritCheckerServiceImpl_PendingChecksByScheme(t *testing.T) {
 
 func TestGerritCheckerServiceImpl_ExecuteCheck(t *testing.T) {
 	// Arrange
+	var options []string
 	gerritServerMock := serverServiceMock{
 		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
 			obj := types.CheckInfo{
@@ -89,18 +91,42 @@ func TestGerritCheckerServiceImpl_ExecuteCheck(t *testing.T) {
 			return body, err
 		},
 		getPathFn: nil,
-		getFn:    nil,
-		initFn:   nil,
-		getURLFn: nil,
+		getFn: func(inputURL *url.URL) ([]byte, error) {
+			options = inputURL.Query()["o"]
+			obj := types.PendingSubmitInfo{
+				Branch:          "master",
+				Owner:           types.AccountInfo{AccountID: 1000000, Username: "jarvis"},
+				CurrentRevision: "184ebe53805e102605d11f6b143486d15c23a09c",
+				Revisions: map[string]types.Revision{
+					"184ebe53805e102605d11f6b143486d15c23a09c": {
+						Number: 1,
+						Ref:    "refs/changes/01/1/1",
+						Commit: types.CommitInfo{Subject: "Add feature"},
+						Files: map[string]types.FileInfo{
+							"/COMMIT_MSG": {},
+							"src/main.go": {},
+							"README.md":   {},
+						},
+					},
+				},
+			}
+			body, err := json.Marshal(&obj)
+			body = append([]byte(")]}'"), body...)
+			return body, err
+		},
+		initFn: nil,
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
 		getRepoRootFn: func() string {
 			return "https://website.com/"
 		},
 	}
 
+	var payload types.TektonListenerPayload
 	eventListenerServerMock := serverServiceMock{
 		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
-			// TODO validate input
-			return []byte{}, nil
+			return []byte{}, json.Unmarshal(content, &payload)
 		},
 		getPathFn:     nil,
 		getFn:         nil,
@@ -131,7 +157,82 @@ func TestGerritCheckerServiceImpl_ExecuteCheck(t *testing.T) {
 	if err != nil {
 		t.Errorf("resulting error expected to be nil, received: %v", err)
 	}
+	if payload.Version != types.CheckPayloadVersion || payload.Branch != "master" ||
+		payload.Revision != "184ebe53805e102605d11f6b143486d15c23a09c" || payload.Ref != "refs/changes/01/1/1" ||
+		payload.Subject != "Add feature" || payload.Owner.Username != "jarvis" {
+		t.Errorf("payload is missing change details: %+v", payload)
+	}
+	if strings.Join(payload.Files, " ") != "README.md src/main.go" {
+		t.Errorf("expected changed files [README.md src/main.go], got: %v", payload.Files)
+	}
+	if strings.Join(options, " ") != "CURRENT_REVISION CURRENT_COMMIT CURRENT_FILES DETAILED_ACCOUNTS" {
+		t.Errorf("expected only the current revision to be fetched, got options: %v", options)
+	}
+
+}
+
+func TestGerritCheckerServiceImpl_ExecuteCheckOlderPatchset(t *testing.T) {
+	// Arrange
+	var filesPath string
+	services.GerritServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			return []byte(")]}'{}"), nil
+		},
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			filesPath = pathing
+			return []byte(`)]}'{"/COMMIT_MSG": {}, "old.go": {}}`), nil
+		},
+		getFn: func(inputURL *url.URL) ([]byte, error) {
+			obj := types.PendingSubmitInfo{
+				Branch:          "master",
+				CurrentRevision: "new",
+				Revisions: map[string]types.Revision{
+					"new": {Number: 2, Ref: "refs/changes/01/1/2", Files: map[string]types.FileInfo{"new.go": {}}},
+				},
+			}
+			if strings.Contains(inputURL.RawQuery, "ALL_REVISIONS") {
+				obj.Revisions["old"] = types.Revision{
+					Number: 1,
+					Ref:    "refs/changes/01/1/1",
+					Commit: types.CommitInfo{Subject: "First try"},
+				}
+			}
+			body, err := json.Marshal(&obj)
+			return append([]byte(")]}'"), body...), err
+		},
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getRepoRootFn: func() string {
+			return "https://website.com/"
+		},
+	}
+	var payload types.TektonListenerPayload
+	services.EventListenerServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			return []byte{}, json.Unmarshal(content, &payload)
+		},
+	}
+	pc := types.PendingChecksInfo{
+		PatchSet: &types.CheckablePatchSetInfo{Repository: "myRepo", ChangeNumber: 1, PatchSetID: 1},
+		PendingChecks: map[string]*types.PendingCheckInfo{
+			"jarvis:jarvispipeline-061bc62acb425af5bc8a4689221eed5781831ecc": {},
+		},
+	}
+
+	// Act
+	err := services.GerritChecker.ExecuteCheck(&pc)
 
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	if payload.Revision != "old" || payload.Ref != "refs/changes/01/1/1" || payload.Subject != "First try" {
+		t.Errorf("expected the payload to describe patchset 1, got: %+v", payload)
+	}
+	if filesPath != "a/changes/1/revisions/old/files" || strings.Join(payload.Files, " ") != "old.go" {
+		t.Errorf("expected the files of patchset 1, got: %v from %q", payload.Files, filesPath)
+	}
 }
 
 func TestGerritCheckerServiceImpl_CheckerPrefix(t *testing.T) {
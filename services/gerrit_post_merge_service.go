@@ -0,0 +1,240 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+const (
+	// mergedQueryOverlap widens each query for merged changes, so changes are not missed due to clock skew.
+	mergedQueryOverlap = time.Minute
+	// mergedRetention is how long merged changes are remembered to avoid handling them twice.
+	mergedRetention = 24 * time.Hour
+	// mergeCommitSearchDepth is how many commits are walked back from the branch tip to find a merge commit.
+	mergeCommitSearchDepth = 20
+)
+
+var (
+	GerritPostMerge gerritPostMergeService = &GerritPostMergeServiceImpl{
+		since:     time.Now(),
+		seen:      map[string]time.Time{},
+		reverting: map[string]bool{},
+	}
+)
+
+type gerritPostMergeService interface {
+	PendingMerged() ([]*types.PendingSubmitInfo, error)
+	ExecutePostMerge(change *types.PendingSubmitInfo) error
+	ReportPostMergeResult(result *types.PipelineResult) error
+}
+
+type GerritPostMergeServiceImpl struct {
+	mu sync.Mutex
+	// since is the time of the previous query for merged changes.
+	since time.Time
+	// seen holds the changes already returned by PendingMerged, with the time they were first seen.
+	seen map[string]time.Time
+	// reverting holds the changes a revert is being proposed for, so concurrent failure reports propose only one.
+	reverting map[string]bool
+}
+
+// PendingMerged returns the changes merged since the previous call, or since the connector started. Each merged
+// change is only returned once.
+func (g *GerritPostMergeServiceImpl) PendingMerged() ([]*types.PendingSubmitInfo, error) {
+	g.mu.Lock()
+	now := time.Now()
+	since := g.since.Add(-mergedQueryOverlap).UTC().Format("2006-01-02 15:04:05 -0700")
+	g.mu.Unlock()
+
+	query := fmt.Sprintf("status:merged since:%q", since)
+	changes, err := GerritChanges.QueryChanges(query, "CURRENT_REVISION", "CURRENT_COMMIT", "LABELS", "DETAILED_ACCOUNTS")
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.since = now
+
+	var merged []*types.PendingSubmitInfo
+	for _, change := range changes {
+		if _, ok := g.seen[change.ID]; ok {
+			continue
+		}
+		g.seen[change.ID] = now
+		merged = append(merged, change)
+	}
+	for id, seen := range g.seen {
+		if now.Sub(seen) > mergedRetention {
+			delete(g.seen, id)
+		}
+	}
+
+	return merged, nil
+}
+
+// ExecutePostMerge sends a "post-merge" event for a merged change, if it was merged by Jarvis or is on one of the
+// configured branches.
+func (g *GerritPostMergeServiceImpl) ExecutePostMerge(change *types.PendingSubmitInfo) error {
+	if !Config.PostMerge.Enabled {
+		return nil
+	}
+	onBranch := false
+	if len(Config.PostMerge.Branches) > 0 {
+		var err error
+		if onBranch, err = matchAny(Config.PostMerge.Branches, change.Branch); err != nil {
+			return err
+		}
+	}
+	if !onBranch && !mergedByJarvis(change) {
+		return nil
+	}
+
+	revision := change.Revisions[change.CurrentRevision]
+	data := types.TektonPostMergePayload{
+		Version:        types.PostMergePayloadVersion,
+		RepoRoot:       GerritServer.GetRepoRoot(),
+		Project:        change.Project,
+		Branch:         change.Branch,
+		ChangeNumber:   strconv.Itoa(change.ChangeNumber),
+		ChangeID:       change.ChangeID,
+		PatchSetNumber: strconv.Itoa(revision.Number),
+		Revision:       change.CurrentRevision,
+		MergeCommit:    mergeCommit(change),
+		Owner:          tektonAccount(change.Owner),
+		Subject:        change.Subject,
+		MergedByJarvis: mergedByJarvis(change),
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	headers := []types.Header{{
+		Key:   "Content-Type",
+		Value: "application/json",
+	}, {
+		Key:   "X-Jarvis",
+		Value: "post-merge",
+	}}
+	if _, err := EventListenerServer.PostPath("", headers, body); err != nil {
+		return err
+	}
+
+	log.Printf("sent post-merge event for change %d at %s", change.ChangeNumber, change.CurrentRevision)
+	return nil
+}
+
+// mergeCommit returns the commit a merged change landed as on its branch. It is the merged revision itself unless
+// the submit type created a merge commit, which is found by walking back the first parents from the branch tip. An
+// empty string is returned when it is not found.
+func mergeCommit(change *types.PendingSubmitInfo) string {
+	branch, err := GerritChanges.GetBranch(change.Project, change.Branch)
+	if err != nil {
+		log.Printf("looking up the merge commit of change %d: %v", change.ChangeNumber, err)
+		return ""
+	}
+
+	commit := branch.Revision
+	for i := 0; i < mergeCommitSearchDepth && commit != ""; i++ {
+		if commit == change.CurrentRevision {
+			return commit
+		}
+		info, err := GerritChanges.GetCommit(change.Project, commit)
+		if err != nil {
+			log.Printf("looking up the merge commit of change %d: %v", change.ChangeNumber, err)
+			return ""
+		}
+		commit = ""
+		for j, parent := range info.Parents {
+			if parent.Commit == change.CurrentRevision && j > 0 {
+				return info.Commit
+			}
+		}
+		if len(info.Parents) > 0 {
+			commit = info.Parents[0].Commit
+		}
+	}
+
+	log.Printf("merge commit of change %d not found on %s", change.ChangeNumber, change.Branch)
+	return ""
+}
+
+// ReportPostMergeResult handles the result reported by a post-merge pipeline. When enabled, a failure on a change
+// merged by Jarvis proposes a revert of it, reviewed by the owner of the original change, unless a revert of it was
+// already proposed.
+func (g *GerritPostMergeServiceImpl) ReportPostMergeResult(result *types.PipelineResult) error {
+	switch result.Status {
+	case PipelineSuccessString:
+		return nil
+	case PipelineFailureString:
+	default:
+		return fmt.Errorf("unknown post-merge result status %q", result.Status)
+	}
+	if !Config.Revert.Enabled {
+		return nil
+	}
+
+	change, err := GerritChanges.GetChange(result.ChangeNumber, "LABELS", "DETAILED_ACCOUNTS")
+	if err != nil {
+		return err
+	}
+	if change.Status != "MERGED" || !mergedByJarvis(change) {
+		log.Printf("not reverting change %s, it was not merged by Jarvis", result.ChangeNumber)
+		return nil
+	}
+
+	g.mu.Lock()
+	if g.reverting[change.ID] {
+		g.mu.Unlock()
+		return nil
+	}
+	g.reverting[change.ID] = true
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		delete(g.reverting, change.ID)
+		g.mu.Unlock()
+	}()
+
+	reverts, err := GerritChanges.QueryChanges(fmt.Sprintf("revertof:%d -status:abandoned", change.ChangeNumber))
+	if err != nil {
+		return err
+	}
+	if len(reverts) > 0 {
+		log.Printf("not reverting change %s, revert %d was already proposed", result.ChangeNumber,
+			reverts[0].ChangeNumber)
+		return nil
+	}
+
+	msg := fmt.Sprintf("Revert \"%s\"\n\nThis reverts change %d, its post-merge pipeline failed.",
+		change.Subject, change.ChangeNumber)
+	if result.Message != "" {
+		msg += "\n\n" + result.Message
+	}
+	if result.URL != "" {
+		msg += "\n\n" + result.URL
+	}
+	revert, err := GerritChanges.Revert(result.ChangeNumber, msg)
+	if err != nil {
+		return err
+	}
+
+	revertID := strconv.Itoa(revert.ChangeNumber)
+	log.Printf("proposed revert %s of change %s", revertID, result.ChangeNumber)
+	if err := GerritChanges.AddReviewer(revertID, strconv.Itoa(change.Owner.AccountID)); err != nil {
+		return err
+	}
+	return GerritChanges.AddHashtags(revertID, Config.Revert.Hashtag)
+}
+
+// mergedByJarvis reports whether the change was locked, and so merged, by Jarvis.
+func mergedByJarvis(change *types.PendingSubmitInfo) bool {
+	return change.Labels["Jarvis-Lock"].Approved.AccountID != 0
+}
@@ -0,0 +1,195 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestGerritPostMergeServiceImpl_PendingMerged(t *testing.T) {
+	// Arrange
+	var queries []string
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			queries = append(queries, u.Query().Get("q"))
+			out := []*types.PendingSubmitInfo{{ID: "merged-1", ChangeNumber: 1}, {ID: "merged-2", ChangeNumber: 2}}
+			body, err := json.Marshal(&out)
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+
+	// Act
+	first, err := services.GerritPostMerge.PendingMerged()
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	second, err := services.GerritPostMerge.PendingMerged()
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+
+	// Assert
+	if len(first) != 2 {
+		t.Errorf("expected 2 merged changes, got: %d", len(first))
+	}
+	if len(second) != 0 {
+		t.Errorf("expected merged changes to be returned only once, got: %d", len(second))
+	}
+	if len(queries) != 2 || !strings.HasPrefix(queries[0], `status:merged since:"`) {
+		t.Errorf("unexpected queries: %v", queries)
+	}
+}
+
+func TestGerritPostMergeServiceImpl_ExecutePostMerge(t *testing.T) {
+	services.Config = services.DefaultConfig()
+	services.Config.PostMerge = types.PostMergeConfig{Enabled: true, Branches: []string{"stable/.*"}}
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	locked := map[string]types.Label{"Jarvis-Lock": {Approved: types.Approval{AccountID: 1000000}}}
+	revision := "184ebe53805e102605d11f6b143486d15c23a09c"
+	testData := []struct {
+		name        string
+		change      types.PendingSubmitInfo
+		commits     map[string]string
+		mergeCommit string
+		sent        bool
+	}{
+		{name: "merged by jarvis", change: types.PendingSubmitInfo{Branch: "master", Labels: locked},
+			mergeCommit: revision, sent: true},
+		{name: "configured branch", change: types.PendingSubmitInfo{Branch: "stable/2023.1"},
+			mergeCommit: revision, sent: true},
+		{name: "merge commit", change: types.PendingSubmitInfo{Branch: "master", Labels: locked},
+			commits: map[string]string{
+				"tip":   `{"commit": "tip", "parents": [{"commit": "merge"}]}`,
+				"merge": `{"commit": "merge", "parents": [{"commit": "base"}, {"commit": "` + revision + `"}]}`,
+			},
+			mergeCommit: "merge", sent: true},
+		{name: "other", change: types.PendingSubmitInfo{Branch: "master"}},
+	}
+
+	for _, test := range testData {
+		// Arrange
+		tip := revision
+		if test.commits != nil {
+			tip = "tip"
+		}
+		services.GerritServer = serverServiceMock{
+			getRepoRootFn: func() string {
+				return "https://website.com/"
+			},
+			getURLFn: func() url.URL {
+				return url.URL{}
+			},
+			getFn: func(u *url.URL) ([]byte, error) {
+				if strings.HasPrefix(u.Path, "a/projects/MyProject/branches/") {
+					return []byte(`)]}'{"revision": "` + tip + `"}`), nil
+				}
+				commit := strings.TrimPrefix(u.Path, "a/projects/MyProject/commits/")
+				return []byte(")]}'" + test.commits[commit]), nil
+			},
+		}
+		var event string
+		var payload types.TektonPostMergePayload
+		services.EventListenerServer = serverServiceMock{
+			postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+				event = headers[1].Value
+				return []byte{}, json.Unmarshal(content, &payload)
+			},
+		}
+		test.change.Project = "MyProject"
+		test.change.ChangeNumber = 10
+		test.change.CurrentRevision = revision
+		test.change.Revisions = map[string]types.Revision{test.change.CurrentRevision: {Number: 3}}
+
+		// Act
+		err := services.GerritPostMerge.ExecutePostMerge(&test.change)
+
+		// Assert
+		if err != nil {
+			t.Errorf("%s: resulting error expected to be nil, received: %v", test.name, err)
+		}
+		if (event == "post-merge") != test.sent {
+			t.Errorf("%s: expected event to be sent: %t, got event: %q", test.name, test.sent, event)
+		}
+		if test.sent && (payload.Revision != test.change.CurrentRevision || payload.Branch != test.change.Branch ||
+			payload.PatchSetNumber != "3" || payload.MergeCommit != test.mergeCommit) {
+			t.Errorf("%s: unexpected payload: %+v", test.name, payload)
+		}
+	}
+}
+
+func TestGerritPostMergeServiceImpl_ReportPostMergeResult(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Revert.Enabled = true
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	var posted []string
+	var bodies []string
+	var queries []string
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			if query := u.Query().Get("q"); query != "" {
+				queries = append(queries, query)
+				if len(posted) == 0 {
+					return []byte(")]}'[]"), nil
+				}
+				return []byte(`)]}'[{"id": "revert", "_number": 11}]`), nil
+			}
+			change := types.PendingSubmitInfo{
+				ID:           "revert-me",
+				ChangeNumber: 10,
+				Status:       "MERGED",
+				Subject:      "Break the build",
+				Owner:        types.AccountInfo{AccountID: 1000001},
+				Labels:       map[string]types.Label{"Jarvis-Lock": {Approved: types.Approval{AccountID: 1000000}}},
+			}
+			body, err := json.Marshal(&change)
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			posted = append(posted, pathing)
+			bodies = append(bodies, string(content))
+			return []byte(`)]}'{"id": "revert", "_number": 11}`), nil
+		},
+	}
+	result := &types.PipelineResult{ChangeNumber: "10", Status: "FAILURE", URL: "https://tekton.local/run/1"}
+
+	// Act
+	err := services.GerritPostMerge.ReportPostMergeResult(result)
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	err = services.GerritPostMerge.ReportPostMergeResult(result)
+
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	expected := []string{"a/changes/10/revert", "a/changes/11/reviewers", "a/changes/11/hashtags"}
+	if strings.Join(posted, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected requests %v, got: %v", expected, posted)
+	}
+	if len(queries) != 2 || queries[0] != "revertof:10 -status:abandoned" {
+		t.Errorf("expected existing reverts to be looked up, got queries: %v", queries)
+	}
+	if !strings.Contains(bodies[0], "https://tekton.local/run/1") {
+		t.Errorf("expected the revert message to link the pipeline run, got: %s", bodies[0])
+	}
+	if !strings.Contains(bodies[1], `"1000001"`) {
+		t.Errorf("expected the owner to be added as reviewer, got: %s", bodies[1])
+	}
+	if !strings.Contains(bodies[2], `"jarvis-revert"`) {
+		t.Errorf("expected the revert hashtag, got: %s", bodies[2])
+	}
+}
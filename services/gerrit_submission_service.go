@@ -2,16 +2,37 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"path"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/att-comdev/jarvis-connector/types"
 )
 
+const (
+	// freezeCondition is the name of the submit condition checking freeze windows.
+	freezeCondition = "freeze"
+
+	// Submit types that rebase the change onto its target branch when it is submitted.
+	submitTypeRebaseIfNecessary = "REBASE_IF_NECESSARY"
+	submitTypeRebaseAlways      = "REBASE_ALWAYS"
+)
+
 var (
 	GerritSubmitter gerritSubmissionService = &GerritSubmissionServiceImpl{}
+
+	// ErrNotLocked is returned for pipeline results about a revision that does not hold the Jarvis-Lock.
+	ErrNotLocked = errors.New("revision is not locked by Jarvis")
+
+	// submitQueryOptions are the change query options providing everything needed to decide on and submit a change.
+	submitQueryOptions = []string{"CURRENT_REVISION", "CURRENT_COMMIT", "SUBMITTABLE", "LABELS", "DETAILED_ACCOUNTS"}
 )
 
 type gerritSubmissionService interface {
@@ -19,66 +40,652 @@ type gerritSubmissionService interface {
 	ExecuteSubmit(patchset *types.PendingSubmitInfo) error
 	PostLock(patchset *types.PendingSubmitInfo) error
 	CallMergePipeline(patchset *types.PendingSubmitInfo) error
+	ReportMergeResult(result *types.PipelineResult) error
+	SubmitInfo(changeID string, force bool) (*types.PendingSubmitInfo, error)
+	Explain(changeID string) (*types.SubmitExplanation, error)
 }
 
-type GerritSubmissionServiceImpl struct{}
+type GerritSubmissionServiceImpl struct {
+	mu sync.Mutex
+	// rebaseConflicts holds the current revision of the candidate changes that could not be rebased, keyed by change
+	// number. They are skipped until a new patchset is uploaded.
+	rebaseConflicts map[int]string
+	// rebased holds the candidate changes Jarvis rebased, keyed by change number. Their checks must pass before they
+	// are submitted, whether or not checks are required.
+	rebased map[int]bool
+}
 
 // PendingSubmit queries and returns all gerrit changes that are pending submission by Jarvis
 func (g *GerritSubmissionServiceImpl) PendingSubmit() ([]*types.PendingSubmitInfo, error) {
-	u := GerritServer.GetURL()
-
-	u.Path = path.Join(u.Path, "a/changes/") + "/"
-	q := u.Query()
-
-	q.Add("o", "CURRENT_REVISION")
-	q.Add("o", "SUBMITTABLE")
-	q.Add("o", "LABELS")
-	q.Add("q", "status:open")
-	u.RawQuery = q.Encode()
-
-	content, err := GerritServer.Get(&u)
-	if err != nil {
-		return []*types.PendingSubmitInfo{}, err
+	seen := map[string]bool{}
+	var out []*types.PendingSubmitInfo
+	for _, query := range g.submitQueries() {
+		changes, err := GerritChanges.QueryChanges(query, submitQueryOptions...)
+		if err != nil {
+			return []*types.PendingSubmitInfo{}, err
+		}
+		for _, change := range changes {
+			if !seen[change.ID] {
+				seen[change.ID] = true
+				out = append(out, change)
+			}
+		}
 	}
 
-	var out []*types.PendingSubmitInfo
-	if err := types.Unmarshal(content, &out); err != nil {
+	g.pruneRebaseState(out)
+	checkers, err := g.roundCheckers()
+	if err != nil {
 		return []*types.PendingSubmitInfo{}, err
 	}
 
 	var patchsets []*types.PendingSubmitInfo
 	for _, obj := range out {
-		// Ignore merge conflicts, patchsets without required labels, and patchsets currently being handled by Jarvis
-		if obj.Mergeable && obj.Subittable && obj.Labels["Jarvis-Lock"].Approved.AccountID == 0 {
+		switch g.failedCondition(obj, checkers) {
+		case "":
+			obj.Priority = matchHashtag(obj, Config.Submit.Hashtags.Priority) != ""
 			patchsets = append(patchsets, obj)
+		case freezeCondition:
+			// Changes that are only held back by a freeze are told so once.
+			g.notifyFrozen(obj)
+		}
+	}
+
+	return g.groupByTopic(g.orderByRelations(patchsets), checkers), nil
+}
+
+// SubmitInfo returns a single change as PendingSubmit would, grouped with the other changes of its topic, for
+// submitting it on demand. It fails if the change, or any open change of its topic, does not meet the submit
+// conditions, unless force is set, in which case the failing conditions are only logged.
+func (g *GerritSubmissionServiceImpl) SubmitInfo(changeID string, force bool) (*types.PendingSubmitInfo, error) {
+	change, err := GerritChanges.GetChange(changeID, submitQueryOptions...)
+	if err != nil {
+		return nil, err
+	}
+	checkers, err := g.roundCheckers()
+	if err != nil {
+		return nil, err
+	}
+	if failed := g.failedCondition(change, checkers); failed != "" {
+		if !force {
+			return nil, fmt.Errorf("change %s does not meet the %s submit condition", changeID, failed)
+		}
+		log.Printf("forcing change %s, which does not meet the %s submit condition", changeID, failed)
+	}
+	change.Priority = matchHashtag(change, Config.Submit.Hashtags.Priority) != ""
+	if change.Topic == "" {
+		return change, nil
+	}
+
+	topic, err := g.openTopicChanges(change.Topic)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range topic {
+		if c.ChangeNumber == change.ChangeNumber {
+			continue
+		}
+		if failed := g.failedCondition(c, checkers); failed != "" {
+			if !force {
+				return nil, fmt.Errorf("topic %q of change %s is not ready to be submitted, change %d does not meet "+
+					"the %s submit condition", change.Topic, changeID, c.ChangeNumber, failed)
+			}
+			log.Printf("forcing change %d, which does not meet the %s submit condition", c.ChangeNumber, failed)
+		}
+	}
+	if len(topic) == 0 {
+		return nil, fmt.Errorf("topic %q of change %s has no open change", change.Topic, changeID)
+	}
+	return topicLead(topic), nil
+}
+
+// Explain evaluates a change against everything PendingSubmit decides on: the submit queries, the change status,
+// every submit condition, its relation chain and its topic.
+func (g *GerritSubmissionServiceImpl) Explain(changeID string) (*types.SubmitExplanation, error) {
+	change, err := GerritChanges.GetChange(changeID, submitQueryOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &types.SubmitExplanation{
+		ChangeNumber: change.ChangeNumber,
+		Project:      change.Project,
+		Branch:       change.Branch,
+		Status:       change.Status,
+		Labels:       change.Labels,
+		Submittable:  true,
+	}
+	add := func(name string, passed bool, details string) {
+		explanation.Conditions = append(explanation.Conditions, types.ConditionResult{
+			Name:    name,
+			Passed:  passed,
+			Details: details,
+		})
+		explanation.Submittable = explanation.Submittable && passed
+	}
+
+	checkers, err := g.roundCheckers()
+	if err != nil {
+		return nil, err
+	}
+	passed, details := g.explainQuery(change)
+	add("query", passed, details)
+	add("open", change.Status == "NEW", "status "+change.Status)
+	for _, condition := range g.submitConditions(checkers) {
+		passed, details := condition.check(change)
+		add(condition.name, passed, details)
+	}
+	passed, details = g.explainAncestors(change, checkers)
+	add("ancestors", passed, details)
+	passed, details = g.explainTopic(change, checkers)
+	add("topic", passed, details)
+
+	return explanation, nil
+}
+
+// explainQuery reports whether the change matches one of the submit queries.
+func (g *GerritSubmissionServiceImpl) explainQuery(change *types.PendingSubmitInfo) (bool, string) {
+	queries := g.submitQueries()
+	for _, query := range queries {
+		matches, err := GerritChanges.QueryChanges(fmt.Sprintf("change:%d %s", change.ChangeNumber, query))
+		if err != nil {
+			return false, fmt.Sprintf("querying %q: %v", query, err)
+		}
+		if len(matches) > 0 {
+			return true, "matches " + query
+		}
+	}
+	return false, "matches none of " + strings.Join(queries, ", ")
+}
+
+// explainAncestors reports whether the open ancestors of the change are ready to be submitted before it.
+func (g *GerritSubmissionServiceImpl) explainAncestors(
+	change *types.PendingSubmitInfo, checkers repoCheckers) (bool, string) {
+	ancestors, err := g.openAncestors(change)
+	if err != nil {
+		return false, fmt.Sprintf("listing related changes: %v", err)
+	}
+	if len(ancestors) == 0 {
+		return true, "no open ancestors"
+	}
+
+	passed := true
+	var details []string
+	for _, number := range ancestors {
+		ancestor, err := GerritChanges.GetChange(strconv.Itoa(number), submitQueryOptions...)
+		if err != nil {
+			return false, fmt.Sprintf("getting ancestor %d: %v", number, err)
+		}
+		if failed := g.failedCondition(ancestor, checkers); failed != "" {
+			passed = false
+			details = append(details, fmt.Sprintf("%d fails %s", number, failed))
+		} else {
+			details = append(details, fmt.Sprintf("%d is ready", number))
+		}
+	}
+	return passed, "open ancestors: " + strings.Join(details, ", ")
+}
+
+// explainTopic reports whether every change of the change's topic is ready to be submitted with it.
+func (g *GerritSubmissionServiceImpl) explainTopic(
+	change *types.PendingSubmitInfo, checkers repoCheckers) (bool, string) {
+	if change.Topic == "" {
+		return true, "no topic"
+	}
+	changes, err := GerritChanges.QueryChanges(fmt.Sprintf("status:open topic:%q", change.Topic),
+		submitQueryOptions...)
+	if err != nil {
+		return false, fmt.Sprintf("querying topic %q: %v", change.Topic, err)
+	}
+
+	var pending []string
+	for _, c := range changes {
+		if failed := g.failedCondition(c, checkers); failed != "" {
+			pending = append(pending, fmt.Sprintf("%d fails %s", c.ChangeNumber, failed))
+		}
+	}
+	if len(pending) > 0 {
+		return false, fmt.Sprintf("topic %q: %s", change.Topic, strings.Join(pending, ", "))
+	}
+	return true, fmt.Sprintf("all %d changes of topic %q are ready", len(changes), change.Topic)
+}
+
+// orderByRelations orders the changes so that parents are submitted before their children. A change is left out
+// while any of its ancestors is neither merged nor part of this submission.
+func (g *GerritSubmissionServiceImpl) orderByRelations(changes []*types.PendingSubmitInfo) []*types.PendingSubmitInfo {
+	queued := map[int]bool{}
+	for _, change := range changes {
+		queued[change.ChangeNumber] = true
+	}
+
+	depth := map[int]int{}
+	priority := map[int]bool{}
+	var ordered []*types.PendingSubmitInfo
+	for _, change := range changes {
+		ancestors, err := g.openAncestors(change)
+		if err != nil {
+			log.Printf("openAncestors(%d): %v", change.ChangeNumber, err)
+			continue
+		}
+
+		blocked := false
+		for _, ancestor := range ancestors {
+			if !queued[ancestor] {
+				log.Printf("change %d waits for its ancestor %d to be merged", change.ChangeNumber, ancestor)
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			depth[change.ChangeNumber] = len(ancestors)
+			ordered = append(ordered, change)
+			if change.Priority {
+				for _, ancestor := range ancestors {
+					priority[ancestor] = true
+				}
+			}
+		}
+	}
+
+	// The ancestors of a priority change are raised along with it, and an ancestor always has fewer open ancestors
+	// than its descendants, so this keeps parents ahead of their children.
+	for _, change := range ordered {
+		change.Priority = change.Priority || priority[change.ChangeNumber]
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority
+		}
+		return depth[ordered[i].ChangeNumber] < depth[ordered[j].ChangeNumber]
+	})
+	return ordered
+}
+
+// openAncestors returns the change numbers of the ancestors of the change's current revision that are not merged.
+func (g *GerritSubmissionServiceImpl) openAncestors(change *types.PendingSubmitInfo) ([]int, error) {
+	related, err := GerritChanges.RelatedChanges(strconv.Itoa(change.ChangeNumber), change.CurrentRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestors []int
+	self := false
+	for _, r := range related {
+		if self && r.Status != "MERGED" {
+			ancestors = append(ancestors, r.ChangeNumber)
+		}
+		if r.ChangeNumber == change.ChangeNumber {
+			self = true
+		}
+	}
+	return ancestors, nil
+}
+
+// submitCondition is a condition a change must meet before Jarvis submits it.
+type submitCondition struct {
+	name string
+	// check reports whether the change meets the condition, and the data it was decided on.
+	check func(change *types.PendingSubmitInfo) (bool, string)
+}
+
+// submitConditions returns the conditions a change must meet, in the order they are evaluated. The checks condition
+// is decided on the given checkers.
+func (g *GerritSubmissionServiceImpl) submitConditions(checkers repoCheckers) []submitCondition {
+	return []submitCondition{
+		{name: "mergeable", check: func(change *types.PendingSubmitInfo) (bool, string) {
+			return change.Mergeable, fmt.Sprintf("mergeable=%t", change.Mergeable)
+		}},
+		{name: "submittable", check: func(change *types.PendingSubmitInfo) (bool, string) {
+			return change.Subittable, fmt.Sprintf("submittable=%t", change.Subittable)
+		}},
+		{name: "unlocked", check: func(change *types.PendingSubmitInfo) (bool, string) {
+			account := change.Labels["Jarvis-Lock"].Approved.AccountID
+			return account == 0, fmt.Sprintf("Jarvis-Lock approved by account %d", account)
+		}},
+		{name: "hashtags", check: checkHashtags},
+		{name: "checks", check: func(change *types.PendingSubmitInfo) (bool, string) {
+			return g.checkJarvisChecks(change, checkers)
+		}},
+		{name: freezeCondition, check: checkFreeze},
+	}
+}
+
+// checkJarvisChecks requires every jarvis checker of the repository to have passed on the current revision, when
+// the connector is configured to gate on checks or Jarvis rebased the change.
+func (g *GerritSubmissionServiceImpl) checkJarvisChecks(
+	change *types.PendingSubmitInfo, checkers repoCheckers) (bool, string) {
+	if !Config.Submit.RequireChecks && !g.rebasedByJarvis(change) {
+		return true, "checks are not required"
+	}
+
+	checks, err := GerritChanges.GetChecks(strconv.Itoa(change.ChangeNumber), change.CurrentRevision)
+	if err != nil {
+		return false, fmt.Sprintf("listing checks: %v", err)
+	}
+
+	states := map[string]string{}
+	for _, check := range checks {
+		states[check.CheckerUUID] = check.State
+	}
+	var details []string
+	passed := true
+	for _, checker := range checkers[change.Project] {
+		state, ok := states[checker.UUID]
+		if !ok {
+			state = NotStartedString
+		}
+		if state != CheckSuccessfulString && state != IrrelevantString {
+			passed = false
+		}
+		details = append(details, fmt.Sprintf("%s=%s", checker.UUID, state))
+	}
+	if len(details) == 0 {
+		return true, "no jarvis checkers for " + change.Project
+	}
+	return passed, strings.Join(details, ", ")
+}
+
+// submittable reports whether Jarvis should submit the change. Merge conflicts, patchsets without required labels,
+// patchsets currently being handled by Jarvis and patchsets excluded by policy are ignored.
+func (g *GerritSubmissionServiceImpl) submittable(change *types.PendingSubmitInfo, checkers repoCheckers) bool {
+	return g.failedCondition(change, checkers) == ""
+}
+
+// failedCondition returns the name of the first condition the change does not meet, or "" if it meets them all.
+func (g *GerritSubmissionServiceImpl) failedCondition(change *types.PendingSubmitInfo, checkers repoCheckers) string {
+	for _, condition := range g.submitConditions(checkers) {
+		if ok, _ := condition.check(change); !ok {
+			return condition.name
 		}
 	}
+	return ""
+}
+
+// checkFreeze holds back changes covered by an active freeze window, remembering the window in change.Frozen.
+func checkFreeze(change *types.PendingSubmitInfo) (bool, string) {
+	window, err := Freeze.ActiveFreeze(change, time.Now())
+	change.Frozen = window
+	if err != nil {
+		return false, fmt.Sprintf("evaluating freeze windows: %v", err)
+	}
+	if window != nil {
+		return false, fmt.Sprintf("frozen by window %q", window.Name)
+	}
+	return true, "no active freeze window"
+}
+
+// notifyFrozen comments on a change held back by the freeze window checkFreeze found.
+func (g *GerritSubmissionServiceImpl) notifyFrozen(change *types.PendingSubmitInfo) {
+	window := change.Frozen
+	if window == nil {
+		return
+	}
+	if err := Freeze.NotifyFrozen(change, window); err != nil {
+		log.Printf("NotifyFrozen(%d, %q): %v", change.ChangeNumber, window.Name, err)
+	}
+}
 
-	return patchsets, nil
+// checkHashtags applies the configured hashtag policy to a change.
+func checkHashtags(change *types.PendingSubmitInfo) (bool, string) {
+	policy := Config.Submit.Hashtags
+	if tag := matchHashtag(change, policy.Exclude); tag != "" {
+		return false, fmt.Sprintf("excluded by hashtag %q", tag)
+	}
+	if len(policy.Require) > 0 && matchHashtag(change, policy.Require) == "" {
+		return false, fmt.Sprintf("hashtags %v do not include any of %v", change.Hashtags, policy.Require)
+	}
+	return true, fmt.Sprintf("hashtags %v", change.Hashtags)
+}
+
+// matchHashtag returns the first hashtag of the change that is in the given list, if any.
+func matchHashtag(change *types.PendingSubmitInfo, hashtags []string) string {
+	for _, tag := range change.Hashtags {
+		for _, h := range hashtags {
+			if tag == h {
+				return tag
+			}
+		}
+	}
+	return ""
+}
+
+// groupByTopic collapses the changes of a topic into a single entry, with the remaining changes of the topic in
+// TopicChanges. A topic is left out until every open change in it is submittable, so it is never merged partially.
+func (g *GerritSubmissionServiceImpl) groupByTopic(
+	changes []*types.PendingSubmitInfo, checkers repoCheckers) []*types.PendingSubmitInfo {
+	var grouped []*types.PendingSubmitInfo
+	topics := map[string]bool{}
+	for _, change := range changes {
+		if change.Topic == "" {
+			grouped = append(grouped, change)
+			continue
+		}
+		if topics[change.Topic] {
+			continue
+		}
+		topics[change.Topic] = true
+
+		topic, err := g.topicChanges(change.Topic, checkers)
+		if err != nil {
+			log.Printf("topicChanges(%q): %v", change.Topic, err)
+			continue
+		}
+		if topic == nil {
+			continue
+		}
+		grouped = append(grouped, topicLead(topic))
+	}
+
+	return grouped
+}
+
+// topicLead returns the first change of a topic, carrying the other changes of the topic. The topic takes priority if
+// any of its changes does.
+func topicLead(topic []*types.PendingSubmitInfo) *types.PendingSubmitInfo {
+	lead := topic[0]
+	lead.TopicChanges = topic[1:]
+	for _, c := range topic {
+		lead.Priority = lead.Priority || matchHashtag(c, Config.Submit.Hashtags.Priority) != ""
+	}
+	return lead
+}
+
+// topicChanges returns all open changes of a topic, or nil if any of them is not submittable.
+func (g *GerritSubmissionServiceImpl) topicChanges(
+	topic string, checkers repoCheckers) ([]*types.PendingSubmitInfo, error) {
+	changes, err := g.openTopicChanges(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		if !g.submittable(change, checkers) {
+			log.Printf("topic %q is not submittable yet, change %d is pending", topic, change.ChangeNumber)
+			return nil, nil
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return changes, nil
+}
+
+// openTopicChanges returns all open changes of a topic, ordered by change number.
+func (g *GerritSubmissionServiceImpl) openTopicChanges(topic string) ([]*types.PendingSubmitInfo, error) {
+	query := fmt.Sprintf("status:open topic:%q", topic)
+	changes, err := GerritChanges.QueryChanges(query, submitQueryOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ChangeNumber < changes[j].ChangeNumber
+	})
+	return changes, nil
+}
+
+// submitQueries returns the change queries for the configured submission scope. Projects with their own
+// query are excluded from the deployment wide query, so each change is only matched by the most specific one.
+func (g *GerritSubmissionServiceImpl) submitQueries() []string {
+	queries := make([]string, 0, len(Config.Submit.Projects)+1)
+	deployment := "(" + Config.Submit.Query + ")"
+	for _, p := range Config.Submit.Projects {
+		queries = append(queries, fmt.Sprintf("(%s) project:%q", p.Query, p.Project))
+		deployment += fmt.Sprintf(" -project:%q", p.Project)
+	}
+
+	return append([]string{deployment}, queries...)
 }
 
 // ExecuteSubmit locks the patchset and sends a request to the Jarvis-System Event listener to trigger the merge
 // pipeline
 func (g *GerritSubmissionServiceImpl) ExecuteSubmit(patchset *types.PendingSubmitInfo) error {
-	if err := g.PostLock(patchset); err != nil {
-		log.Printf("PostLock Error: %v", err)
-		return err
+	changes := append([]*types.PendingSubmitInfo{patchset}, patchset.TopicChanges...)
+
+	for _, change := range changes {
+		if g.rebaseConflicted(change) {
+			log.Printf("change %d could not be rebased, waiting for a new patchset", change.ChangeNumber)
+			return nil
+		}
+	}
+
+	// Changes that had to be rebased are submitted in a later round, once checks passed on the new patchset.
+	wait := false
+	for _, change := range changes {
+		w, err := g.rebaseIfBehind(change)
+		if err != nil {
+			log.Printf("rebaseIfBehind Error: %v", err)
+			return err
+		}
+		wait = wait || w
+	}
+	if wait {
+		return nil
+	}
+
+	// Lock every change of a topic before any of them is handed to the merge pipeline.
+	for i, change := range changes {
+		if err := g.PostLock(change); err != nil {
+			log.Printf("PostLock Error: %v", err)
+			g.unlockAll(changes[:i], fmt.Sprintf("Jarvis could not lock change %d of this topic, "+
+				"the topic is not submitted: %v", change.ChangeNumber, err))
+			return err
+		}
 	}
 
 	if err := g.CallMergePipeline(patchset); err != nil {
 		log.Printf("CallMergePipeline Error: %v", err)
+		g.unlockAll(changes, fmt.Sprintf("Jarvis could not start the merge pipeline, "+
+			"the change is not submitted: %v", err))
 		return err
 	}
 
 	return nil
 }
 
+// rebaseIfBehind rebases changes of projects using a rebase submit type when their target branch moved on. Changes
+// stacked on another open change are left to be rebased with their ancestors. It reports whether the change has to
+// wait for a later round: a rebased change waits for the checks of its new patchset, which are picked up as pending
+// checks, and a change that cannot be rebased is told so once and skipped until a new patchset is uploaded.
+func (g *GerritSubmissionServiceImpl) rebaseIfBehind(change *types.PendingSubmitInfo) (bool, error) {
+	if change.SubmitType != submitTypeRebaseIfNecessary && change.SubmitType != submitTypeRebaseAlways {
+		return false, nil
+	}
+
+	branch, err := GerritChanges.GetBranch(change.Project, change.Branch)
+	if err != nil {
+		return false, err
+	}
+	parents := change.Revisions[change.CurrentRevision].Commit.Parents
+	if len(parents) > 0 && parents[0].Commit == branch.Revision {
+		return false, nil
+	}
+	ancestors, err := g.openAncestors(change)
+	if err != nil {
+		return false, err
+	}
+	if len(ancestors) > 0 {
+		return false, nil
+	}
+
+	changeID := strconv.Itoa(change.ChangeNumber)
+	log.Printf("rebasing change %s onto %s of %s", changeID, branch.Revision, change.Branch)
+	err = GerritChanges.Rebase(changeID)
+	statusErr, ok := err.(*StatusError)
+	if err == nil {
+		g.mu.Lock()
+		if g.rebased == nil {
+			g.rebased = map[int]bool{}
+		}
+		g.rebased[change.ChangeNumber] = true
+		g.mu.Unlock()
+		return true, nil
+	}
+	if !ok || statusErr.StatusCode != http.StatusConflict {
+		return false, err
+	}
+	if strings.Contains(statusErr.Body, "up to date") {
+		return false, nil
+	}
+
+	log.Printf("change %s cannot be rebased: %s", changeID, statusErr.Body)
+	g.mu.Lock()
+	if g.rebaseConflicts == nil {
+		g.rebaseConflicts = map[int]string{}
+	}
+	g.rebaseConflicts[change.ChangeNumber] = change.CurrentRevision
+	g.mu.Unlock()
+	revision := strconv.Itoa(change.Revisions[change.CurrentRevision].Number)
+	err = GerritChanges.PostReview(changeID, revision, &types.ReviewInput{
+		Message: fmt.Sprintf("Jarvis could not rebase this change onto %s and will not submit it until a new "+
+			"patchset is uploaded: %s", change.Branch, statusErr.Body),
+		Tag: jarvisTag,
+	})
+	return true, err
+}
+
+// rebaseConflicted reports whether the current revision of the change could not be rebased.
+func (g *GerritSubmissionServiceImpl) rebaseConflicted(change *types.PendingSubmitInfo) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	revision, ok := g.rebaseConflicts[change.ChangeNumber]
+	return ok && revision == change.CurrentRevision
+}
+
+// rebasedByJarvis reports whether Jarvis rebased the change.
+func (g *GerritSubmissionServiceImpl) rebasedByJarvis(change *types.PendingSubmitInfo) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rebased[change.ChangeNumber]
+}
+
+// pruneRebaseState forgets the rebase conflicts of changes that are no longer candidates, or got a new patchset, and
+// the rebased changes that are no longer candidates.
+func (g *GerritSubmissionServiceImpl) pruneRebaseState(candidates []*types.PendingSubmitInfo) {
+	current := map[int]string{}
+	for _, change := range candidates {
+		current[change.ChangeNumber] = change.CurrentRevision
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for number, revision := range g.rebaseConflicts {
+		if current[number] != revision {
+			delete(g.rebaseConflicts, number)
+		}
+	}
+	for number := range g.rebased {
+		if _, ok := current[number]; !ok {
+			delete(g.rebased, number)
+		}
+	}
+}
+
 // ExecuteSubmit locks the patchset by adding the 'Jarvis-Lock' label
 func (g *GerritSubmissionServiceImpl) PostLock(patchset *types.PendingSubmitInfo) error {
 	u := GerritServer.GetURL()
 	u.Path = path.Join(u.Path, fmt.Sprintf(
 		"a/changes/%s/revisions/%s/review",
-		patchset.ChangeID,
+		// The Change-Id is ambiguous once a change is backported, so the change is addressed by its number.
+		strconv.Itoa(patchset.ChangeNumber),
 		strconv.Itoa(patchset.Revisions[patchset.CurrentRevision].Number)), // The patchset number of current revision
 	) + "/"
 	lockPayload := types.LockPayload{
@@ -94,15 +701,28 @@ func (g *GerritSubmissionServiceImpl) PostLock(patchset *types.PendingSubmitInfo
 	body, err := json.Marshal(lockPayload)
 	if err != nil {
 		log.Printf("json.Marshal Error: %v", err)
+		return err
 	}
 	_, err = GerritServer.PostPath(u.Path, headers, body)
 	if err != nil {
 		log.Printf("PostPath Path Error: %v", err)
+		return err
 	}
 
 	return nil
 }
 
+// unlockAll releases the lock of the given changes, explaining why in a message.
+func (g *GerritSubmissionServiceImpl) unlockAll(changes []*types.PendingSubmitInfo, msg string) {
+	for _, change := range changes {
+		changeID := strconv.Itoa(change.ChangeNumber)
+		revision := strconv.Itoa(change.Revisions[change.CurrentRevision].Number)
+		if err := g.postUnlock(changeID, revision, msg); err != nil {
+			log.Printf("postUnlock(%s, %s): %v", changeID, revision, err)
+		}
+	}
+}
+
 // CallMergePipeline sends a request to the Jarvis-System Event listener to trigger the merge pipeline
 func (g *GerritSubmissionServiceImpl) CallMergePipeline(patchset *types.PendingSubmitInfo) error {
 	checkerUUID, err := g.getChecker(patchset.Project)
@@ -110,12 +730,38 @@ func (g *GerritSubmissionServiceImpl) CallMergePipeline(patchset *types.PendingS
 		log.Printf("error finding relevant checker UUID: %v", err)
 	}
 
+	revision := patchset.Revisions[patchset.CurrentRevision]
 	data := types.TektonMergePayload{
+		Version:        types.MergePayloadVersion,
 		RepoRoot:       GerritServer.GetRepoRoot(),
 		Project:        patchset.Project,
+		Branch:         patchset.Branch,
 		ChangeNumber:   strconv.Itoa(patchset.ChangeNumber),
-		PatchSetNumber: strconv.Itoa(patchset.Revisions[patchset.CurrentRevision].Number),
+		ChangeID:       patchset.ChangeID,
+		PatchSetNumber: strconv.Itoa(revision.Number),
+		Revision:       patchset.CurrentRevision,
+		Ref:            revision.Ref,
+		SubmitType:     patchset.SubmitType,
+		Owner:          tektonAccount(patchset.Owner),
+		Hashtags:       patchset.Hashtags,
 		CheckerUUID:    checkerUUID,
+		// Tells the pipeline to report back instead of submitting the change itself.
+		ConnectorSubmit: Config.Submit.Mode == SubmitModeConnector,
+		Priority:        patchset.Priority,
+	}
+	if patchset.Topic != "" {
+		data.Topic = patchset.Topic
+		for _, change := range append([]*types.PendingSubmitInfo{patchset}, patchset.TopicChanges...) {
+			data.Changes = append(data.Changes, types.TektonMergeChange{
+				Project:        change.Project,
+				Branch:         change.Branch,
+				ChangeNumber:   strconv.Itoa(change.ChangeNumber),
+				ChangeID:       change.ChangeID,
+				PatchSetNumber: strconv.Itoa(change.Revisions[change.CurrentRevision].Number),
+				Revision:       change.CurrentRevision,
+				Ref:            change.Revisions[change.CurrentRevision].Ref,
+			})
+		}
 	}
 
 	headers := []types.Header{{
@@ -126,16 +772,164 @@ func (g *GerritSubmissionServiceImpl) CallMergePipeline(patchset *types.PendingS
 		Value: "merge",
 	}}
 
-	body, err := json.Marshal(data) //nolint
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
 	_, err = EventListenerServer.PostPath("", headers, body)
+	return err
+}
 
-	if err != nil {
-		log.Printf("error sending request to Gerrit: %v", err)
+// ReportMergeResult handles the result reported by the merge pipeline, for the change and the other changes of its
+// topic. When the connector is responsible for submitting, a successful result submits the locked revisions.
+// Failures are reported as a comment on the changes that were not submitted, and their lock is released. Results for
+// a revision that does not hold the Jarvis-Lock are refused with ErrNotLocked.
+func (g *GerritSubmissionServiceImpl) ReportMergeResult(result *types.PipelineResult) error {
+	changes := resultChanges(result)
+	for _, change := range changes {
+		if change.ChangeNumber == "" || change.PatchSetNumber == "" {
+			return fmt.Errorf("merge result is missing the change or patchset number: %+v", result)
+		}
+	}
+
+	if result.Status != PipelineSuccessString && result.Status != PipelineFailureString {
+		return fmt.Errorf("unknown merge result status %q", result.Status)
+	}
+	if result.Status == PipelineSuccessString && Config.Submit.Mode != SubmitModeConnector {
+		return nil
+	}
+	// Only revisions Jarvis locked for the merge pipeline are submitted or unlocked.
+	for _, change := range changes {
+		if err := checkLocked(change.ChangeNumber, change.PatchSetNumber); err != nil {
+			return err
+		}
+	}
+
+	var msg string
+	switch result.Status {
+	case PipelineSuccessString:
+		remaining, err := g.submitAll(changes)
+		if err == nil {
+			return nil
+		}
+		msg = submitFailureMessage(err)
+		changes = remaining
+	case PipelineFailureString:
+		msg = "Jarvis merge pipeline failed"
+		if result.Message != "" {
+			msg += ": " + result.Message
+		}
+	}
+
+	if result.URL != "" {
+		msg += "\n\n" + result.URL
+	}
+	var unlockErr error
+	for _, change := range changes {
+		if err := g.postUnlock(change.ChangeNumber, change.PatchSetNumber, msg); err != nil {
+			log.Printf("postUnlock(%s, %s): %v", change.ChangeNumber, change.PatchSetNumber, err)
+			unlockErr = err
+		}
 	}
+	return unlockErr
+}
 
+// resultChanges returns the revisions a merge result is about, the reported change first.
+func resultChanges(result *types.PipelineResult) []types.PipelineChange {
+	changes := []types.PipelineChange{{ChangeNumber: result.ChangeNumber, PatchSetNumber: result.PatchSetNumber}}
+	for _, change := range result.Changes {
+		if change.ChangeNumber != result.ChangeNumber {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// submitAll submits the changes in order. A change merged along with an earlier one, as Gerrit does for topics when
+// submitWholeTopic is set, counts as submitted. When a change cannot be submitted, it returns the error and the
+// changes that were left unsubmitted, starting with that one.
+func (g *GerritSubmissionServiceImpl) submitAll(changes []types.PipelineChange) ([]types.PipelineChange, error) {
+	for i, change := range changes {
+		err := GerritChanges.SubmitRevision(change.ChangeNumber, change.PatchSetNumber)
+		if err != nil {
+			if current, getErr := GerritChanges.GetChange(change.ChangeNumber); getErr != nil ||
+				current.Status != "MERGED" {
+				log.Printf("SubmitRevision(%s, %s): %v", change.ChangeNumber, change.PatchSetNumber, err)
+				return changes[i:], err
+			}
+		}
+		log.Printf("submitted change %s at patchset %s", change.ChangeNumber, change.PatchSetNumber)
+	}
+	return nil, nil
+}
+
+// checkLocked fails with ErrNotLocked unless the revision is the current one of the change and holds the Jarvis-Lock.
+func checkLocked(changeID, revision string) error {
+	change, err := GerritChanges.GetChange(changeID, "CURRENT_REVISION", "LABELS")
+	if err != nil {
+		return err
+	}
+	current := strconv.Itoa(change.Revisions[change.CurrentRevision].Number)
+	if current != revision || change.Labels["Jarvis-Lock"].Approved.AccountID == 0 {
+		return fmt.Errorf("change %s patchset %s: %w", changeID, revision, ErrNotLocked)
+	}
 	return nil
 }
 
+// postUnlock removes the 'Jarvis-Lock' label, explaining why in a message.
+func (g *GerritSubmissionServiceImpl) postUnlock(changeID, revision, msg string) error {
+	return GerritChanges.PostReview(changeID, revision, &types.ReviewInput{
+		Message: msg,
+		Labels:  map[string]string{"Jarvis-Lock": "0"},
+		Tag:     jarvisTag,
+	})
+}
+
+// submitFailureMessage describes why Gerrit refused to submit a change.
+func submitFailureMessage(err error) string {
+	statusErr, ok := err.(*StatusError)
+	if !ok {
+		return fmt.Sprintf("Jarvis could not submit this change: %v", err)
+	}
+	if statusErr.StatusCode == http.StatusConflict {
+		return "Jarvis could not submit this change, it conflicts with the target branch or is no longer " +
+			"submittable: " + statusErr.Body
+	}
+	return fmt.Sprintf("Jarvis could not submit this change, Gerrit returned status %d: %s",
+		statusErr.StatusCode, statusErr.Body)
+}
+
+// repoCheckers holds the enabled checkers of our scheme, keyed by repository.
+type repoCheckers map[string][]*types.CheckerInfo
+
+// roundCheckers returns the checkers the checks condition is decided on, fetched once for a whole round of submit
+// decisions. Nothing is fetched when checks are not required and Jarvis rebased no change.
+func (g *GerritSubmissionServiceImpl) roundCheckers() (repoCheckers, error) {
+	g.mu.Lock()
+	rebased := len(g.rebased) > 0
+	g.mu.Unlock()
+	if !Config.Submit.RequireChecks && !rebased {
+		return nil, nil
+	}
+
+	content, err := GerritServer.GetPath("a/plugins/checks/checkers/", jsonHeaders())
+	if err != nil {
+		return nil, err
+	}
+	var out []*types.CheckerInfo
+	if err := types.Unmarshal(content, &out); err != nil {
+		return nil, err
+	}
+
+	checkers := repoCheckers{}
+	for _, checker := range out {
+		if checker.Status == CheckerEnabledString && strings.HasPrefix(checker.UUID, checkerScheme+":") {
+			checkers[checker.Repository] = append(checkers[checker.Repository], checker)
+		}
+	}
+	return checkers, nil
+}
+
 // getChecker returns the checker UUID associated with a given repository
 // Warning: This method assumes only one checker exists per repository.
 func (g *GerritSubmissionServiceImpl) getChecker(repository string) (string, error) {
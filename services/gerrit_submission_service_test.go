@@ -2,9 +2,13 @@ package services_test
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/att-comdev/jarvis-connector/services"
 	"github.com/att-comdev/jarvis-connector/types"
 	"net/url"
+	"path"
+	"strings"
 	"testing"
 )
 
@@ -23,6 +27,9 @@ func TestGerritSubmissionServiceImpl_PendingSubmit(t *testing.T) {
 	labels := map[string]types.Label{}
 
 	serverMock.getFn = func(u *url.URL) ([]byte, error) {
+		if body, ok := relatedChangesResponse(u); ok {
+			return body, nil
+		}
 		// TODO verify Input
 		out := []*types.PendingSubmitInfo{{
 			ID:              "ID-1",
@@ -81,6 +88,9 @@ func TestGerritSubmissionServiceImpl_PendingSubmit2(t *testing.T) {
 	}
 
 	serverMock.getFn = func(u *url.URL) ([]byte, error) {
+		if body, ok := relatedChangesResponse(u); ok {
+			return body, nil
+		}
 		// TODO verify Input
 		out := []*types.PendingSubmitInfo{{
 			ID:              "ID-1",
@@ -177,8 +187,8 @@ func TestGerritSubmissionServiceImpl_ExecuteSubmit(t *testing.T) {
 
 			return body, nil
 		},
-		getFn:    nil,
-		initFn:   nil,
+		getFn:  nil,
+		initFn: nil,
 		getURLFn: func() url.URL {
 			return *u
 		},
@@ -233,9 +243,11 @@ func TestGerritSubmissionServiceImpl_ExecuteSubmit(t *testing.T) {
 func TestGerritSubmissionServiceImpl_PostLock(t *testing.T) {
 	// Arrange
 	u, _ := url.Parse("https://website.com")
+	var posted string
 	serverMock := serverServiceMock{
 		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
 			// TODO verify Input
+			posted = pathing
 			return []byte(")]}'"), nil
 		},
 		getPathFn: nil,
@@ -276,6 +288,10 @@ func TestGerritSubmissionServiceImpl_PostLock(t *testing.T) {
 	if err != nil {
 		t.Errorf("resulting error expected to be nil, received: %v", err)
 	}
+	// Backports share the Change-Id of the original change, so the lock addresses the change number.
+	if posted != "a/changes/10/revisions/1/review/" {
+		t.Errorf("expected the change to be locked by its number, got: %q", posted)
+	}
 }
 
 func TestGerritSubmissionServiceImpl_CallMergePipeline(t *testing.T) {
@@ -354,3 +370,1002 @@ func TestGerritSubmissionServiceImpl_CallMergePipeline(t *testing.T) {
 		t.Errorf("resulting error expected to be nil, received: %v", err)
 	}
 }
+
+func TestGerritSubmissionServiceImpl_PendingSubmitProjectQueries(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Submit.Projects = []types.ProjectSubmitConfig{{
+		Project: "^airship/.*",
+		Query:   "status:open label:Code-Review=+2",
+	}}
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	var queries []string
+	serverMock := serverServiceMock{}
+	serverMock.getURLFn = func() url.URL {
+		mockedURL, _ := url.Parse("https://website.com")
+		return *mockedURL
+	}
+	serverMock.getFn = func(u *url.URL) ([]byte, error) {
+		if body, ok := relatedChangesResponse(u); ok {
+			return body, nil
+		}
+		queries = append(queries, u.Query().Get("q"))
+		// Both queries return the same change, it must only be reported once.
+		out := []*types.PendingSubmitInfo{{
+			ID:         "ID-1",
+			Mergeable:  true,
+			Subittable: true,
+		}}
+		body, err := json.Marshal(&out)
+		body = append([]byte(")]}'"), body...)
+		return body, err
+	}
+
+	services.GerritServer = serverMock
+
+	// Act
+	result, err := services.GerritSubmitter.PendingSubmit()
+
+	// Assert
+	if err != nil {
+		t.Errorf("Received error from PendingSubmit function: %v", err)
+	}
+	if len(result) != 1 {
+		t.Errorf("result length is expected to be 1, got: %d", len(result))
+	}
+	expected := []string{
+		`(status:open) -project:"^airship/.*"`,
+		`(status:open label:Code-Review=+2) project:"^airship/.*"`,
+	}
+	if len(queries) != len(expected) {
+		t.Fatalf("expected queries %v, got: %v", expected, queries)
+	}
+	for i := range expected {
+		if queries[i] != expected[i] {
+			t.Errorf("expected query %q, got: %q", expected[i], queries[i])
+		}
+	}
+}
+
+func TestGerritSubmissionServiceImpl_ReportMergeResult(t *testing.T) {
+	services.Config = services.DefaultConfig()
+	services.Config.Submit.Mode = services.SubmitModeConnector
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	locked := `)]}'{"_number": 10, "current_revision": "abc", "revisions": {"abc": {"_number": 2}},
+		"labels": {"Jarvis-Lock": {"approved": {"_account_id": 1}}}}`
+	testData := []struct {
+		name         string
+		status       string
+		change       string
+		submitStatus int
+		expected     []string
+		notLocked    bool
+	}{
+		{
+			name:         "submitted",
+			status:       services.PipelineSuccessString,
+			change:       locked,
+			submitStatus: 200,
+			expected:     []string{"a/changes/10/revisions/2/submit"},
+		}, {
+			name:         "conflict",
+			status:       services.PipelineSuccessString,
+			change:       locked,
+			submitStatus: 409,
+			expected:     []string{"a/changes/10/revisions/2/submit", "a/changes/10/revisions/2/review"},
+		}, {
+			name:     "pipeline failed",
+			status:   services.PipelineFailureString,
+			change:   locked,
+			expected: []string{"a/changes/10/revisions/2/review"},
+		}, {
+			name:      "not locked",
+			status:    services.PipelineSuccessString,
+			change:    `)]}'{"_number": 10, "current_revision": "abc", "revisions": {"abc": {"_number": 2}}}`,
+			notLocked: true,
+		}, {
+			name:   "newer patchset",
+			status: services.PipelineFailureString,
+			change: `)]}'{"_number": 10, "current_revision": "def", "revisions": {"def": {"_number": 3}},
+				"labels": {"Jarvis-Lock": {"approved": {"_account_id": 1}}}}`,
+			notLocked: true,
+		},
+	}
+
+	for _, test := range testData {
+		// Arrange
+		var paths []string
+		var review types.ReviewInput
+		submitStatus := test.submitStatus
+		change := test.change
+		services.GerritServer = serverServiceMock{
+			getURLFn: func() url.URL {
+				return url.URL{}
+			},
+			getFn: func(u *url.URL) ([]byte, error) {
+				return []byte(change), nil
+			},
+			postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+				paths = append(paths, pathing)
+				if strings.HasSuffix(pathing, "/submit") && submitStatus != 200 {
+					return nil, &services.StatusError{StatusCode: submitStatus, Body: "merge conflict"}
+				}
+				if strings.HasSuffix(pathing, "/review") {
+					if err := json.Unmarshal(content, &review); err != nil {
+						t.Errorf("%s: review is not valid JSON: %v", test.name, err)
+					}
+				}
+				return []byte(")]}'{}"), nil
+			},
+		}
+
+		// Act
+		err := services.GerritSubmitter.ReportMergeResult(&types.PipelineResult{
+			ChangeNumber:   "10",
+			PatchSetNumber: "2",
+			Status:         test.status,
+		})
+
+		// Assert
+		if test.notLocked {
+			if !errors.Is(err, services.ErrNotLocked) {
+				t.Errorf("%s: expected ErrNotLocked, received: %v", test.name, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: resulting error expected to be nil, received: %v", test.name, err)
+		}
+		if strings.Join(paths, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("%s: expected requests %v, got: %v", test.name, test.expected, paths)
+		}
+		if !test.notLocked && (len(paths) > 1 || test.status == services.PipelineFailureString) {
+			if review.Labels["Jarvis-Lock"] != "0" || review.Message == "" {
+				t.Errorf("%s: expected the lock to be released with a message, got: %+v", test.name, review)
+			}
+		}
+	}
+}
+
+func TestGerritSubmissionServiceImpl_ReportMergeResultTopic(t *testing.T) {
+	services.Config = services.DefaultConfig()
+	services.Config.Submit.Mode = services.SubmitModeConnector
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	testData := []struct {
+		name     string
+		status   string
+		merged   bool
+		expected []string
+	}{
+		{
+			name:     "submitted",
+			status:   services.PipelineSuccessString,
+			expected: []string{"a/changes/1/revisions/3/submit", "a/changes/2/revisions/4/submit"},
+		}, {
+			name:     "submitted whole topic",
+			status:   services.PipelineSuccessString,
+			merged:   true,
+			expected: []string{"a/changes/1/revisions/3/submit", "a/changes/2/revisions/4/submit"},
+		}, {
+			name:     "pipeline failed",
+			status:   services.PipelineFailureString,
+			expected: []string{"a/changes/1/revisions/3/review", "a/changes/2/revisions/4/review"},
+		},
+	}
+
+	for _, test := range testData {
+		// Arrange
+		var paths []string
+		merged := test.merged
+		services.GerritServer = serverServiceMock{
+			getURLFn: func() url.URL {
+				return url.URL{}
+			},
+			getFn: func(u *url.URL) ([]byte, error) {
+				number := path.Base(u.Path)
+				status := "NEW"
+				if merged && number == "2" {
+					status = "MERGED"
+				}
+				return []byte(fmt.Sprintf(`)]}'{"status": %q, "current_revision": "rev",
+					"revisions": {"rev": {"_number": %d}}, "labels": {"Jarvis-Lock": {"approved": {"_account_id": 1}}}}`,
+					status, map[string]int{"1": 3, "2": 4}[number])), nil
+			},
+			postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+				paths = append(paths, pathing)
+				if merged && pathing == "a/changes/2/revisions/4/submit" {
+					return nil, &services.StatusError{StatusCode: 409, Body: "change is merged"}
+				}
+				return []byte(")]}'{}"), nil
+			},
+		}
+
+		// Act
+		err := services.GerritSubmitter.ReportMergeResult(&types.PipelineResult{
+			ChangeNumber:   "1",
+			PatchSetNumber: "3",
+			Status:         test.status,
+			Changes: []types.PipelineChange{
+				{ChangeNumber: "1", PatchSetNumber: "3"},
+				{ChangeNumber: "2", PatchSetNumber: "4"},
+			},
+		})
+
+		// Assert
+		if err != nil {
+			t.Errorf("%s: resulting error expected to be nil, received: %v", test.name, err)
+		}
+		if strings.Join(paths, " ") != strings.Join(test.expected, " ") {
+			t.Errorf("%s: expected requests %v, got: %v", test.name, test.expected, paths)
+		}
+	}
+}
+
+func TestGerritSubmissionServiceImpl_ExecuteSubmitLockFailure(t *testing.T) {
+	// Arrange
+	var posts []string
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			posts = append(posts, pathing)
+			if pathing == "a/changes/2/revisions/3/review/" {
+				return nil, &services.StatusError{StatusCode: 403, Body: "forbidden"}
+			}
+			return []byte(")]}'{}"), nil
+		},
+	}
+	var events int
+	services.EventListenerServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			events++
+			return []byte{}, nil
+		},
+	}
+	revisions := map[string]types.Revision{"rev": {Number: 3}}
+	testingPatchset := &types.PendingSubmitInfo{
+		ChangeNumber:    1,
+		ChangeID:        "ID-1",
+		Topic:           "complete",
+		CurrentRevision: "rev",
+		Revisions:       revisions,
+		TopicChanges: []*types.PendingSubmitInfo{{
+			ChangeNumber:    2,
+			ChangeID:        "ID-2",
+			Topic:           "complete",
+			CurrentRevision: "rev",
+			Revisions:       revisions,
+		}},
+	}
+
+	// Act
+	err := services.GerritSubmitter.ExecuteSubmit(testingPatchset)
+
+	// Assert
+	if err == nil {
+		t.Errorf("expected an error when a change of the topic cannot be locked")
+	}
+	expected := "a/changes/1/revisions/3/review/ a/changes/2/revisions/3/review/ a/changes/1/revisions/3/review"
+	if strings.Join(posts, " ") != expected {
+		t.Errorf("expected the locked change to be unlocked again, got: %v", posts)
+	}
+	if events != 0 {
+		t.Errorf("expected no merge event, got: %d", events)
+	}
+}
+
+func TestGerritSubmissionServiceImpl_ExecuteSubmitDispatchFailure(t *testing.T) {
+	// Arrange
+	var posts []string
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			return []byte(")]}'[]"), nil
+		},
+		getRepoRootFn: func() string {
+			return "https://website.com/"
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			posts = append(posts, pathing)
+			return []byte(")]}'{}"), nil
+		},
+	}
+	services.EventListenerServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			return nil, &services.StatusError{StatusCode: 503, Body: "unavailable"}
+		},
+	}
+	revisions := map[string]types.Revision{"rev": {Number: 3}}
+	testingPatchset := &types.PendingSubmitInfo{
+		ChangeNumber:    1,
+		ChangeID:        "ID-1",
+		Topic:           "complete",
+		CurrentRevision: "rev",
+		Revisions:       revisions,
+		TopicChanges: []*types.PendingSubmitInfo{{
+			ChangeNumber:    2,
+			ChangeID:        "ID-2",
+			Topic:           "complete",
+			CurrentRevision: "rev",
+			Revisions:       revisions,
+		}},
+	}
+
+	// Act
+	err := services.GerritSubmitter.ExecuteSubmit(testingPatchset)
+
+	// Assert
+	if err == nil {
+		t.Errorf("expected an error when the merge event cannot be sent")
+	}
+	expected := "a/changes/1/revisions/3/review/ a/changes/2/revisions/3/review/ " +
+		"a/changes/1/revisions/3/review a/changes/2/revisions/3/review"
+	if strings.Join(posts, " ") != expected {
+		t.Errorf("expected every change of the topic to be unlocked again, got: %v", posts)
+	}
+}
+
+func TestGerritSubmissionServiceImpl_PendingSubmitTopics(t *testing.T) {
+	// Arrange
+	topics := map[string][]*types.PendingSubmitInfo{
+		"complete": {
+			{ID: "ID-1", ChangeNumber: 1, Topic: "complete", Mergeable: true, Subittable: true},
+			{ID: "ID-2", ChangeNumber: 2, Topic: "complete", Mergeable: true, Subittable: true},
+		},
+		"partial": {
+			{ID: "ID-3", ChangeNumber: 3, Topic: "partial", Mergeable: true, Subittable: true},
+			{ID: "ID-4", ChangeNumber: 4, Topic: "partial", Mergeable: true, Subittable: false},
+		},
+	}
+	serverMock := serverServiceMock{}
+	serverMock.getURLFn = func() url.URL {
+		mockedURL, _ := url.Parse("https://website.com")
+		return *mockedURL
+	}
+	serverMock.getFn = func(u *url.URL) ([]byte, error) {
+		if body, ok := relatedChangesResponse(u); ok {
+			return body, nil
+		}
+		var out []*types.PendingSubmitInfo
+		switch u.Query().Get("q") {
+		case `status:open topic:"complete"`:
+			out = topics["complete"]
+		case `status:open topic:"partial"`:
+			out = topics["partial"]
+		default:
+			out = []*types.PendingSubmitInfo{
+				topics["complete"][1], topics["complete"][0], topics["partial"][0],
+				{ID: "ID-5", ChangeNumber: 5, Mergeable: true, Subittable: true},
+			}
+		}
+		body, err := json.Marshal(&out)
+		body = append([]byte(")]}'"), body...)
+		return body, err
+	}
+
+	services.GerritServer = serverMock
+
+	// Act
+	result, err := services.GerritSubmitter.PendingSubmit()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received error from PendingSubmit function: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("result length is expected to be 2, got: %d", len(result))
+	}
+	if result[0].ChangeNumber != 1 || len(result[0].TopicChanges) != 1 || result[0].TopicChanges[0].ChangeNumber != 2 {
+		t.Errorf("expected change 1 to lead topic 'complete' with change 2, got: %+v", result[0])
+	}
+	if result[1].ChangeNumber != 5 {
+		t.Errorf("expected change 5 without topic, got: %d", result[1].ChangeNumber)
+	}
+}
+
+func TestGerritSubmissionServiceImpl_SubmitInfo(t *testing.T) {
+	// Arrange
+	changes := map[string]*types.PendingSubmitInfo{
+		"1": {ID: "ID-1", ChangeNumber: 1, Mergeable: true, Subittable: true},
+		"2": {ID: "ID-2", ChangeNumber: 2, Mergeable: true, Subittable: false},
+		"3": {ID: "ID-3", ChangeNumber: 3, Topic: "partial", Mergeable: true, Subittable: true},
+		"4": {ID: "ID-4", ChangeNumber: 4, Topic: "partial", Mergeable: true, Subittable: false},
+	}
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			var body []byte
+			var err error
+			if u.Query().Get("q") == `status:open topic:"partial"` {
+				body, err = json.Marshal([]*types.PendingSubmitInfo{changes["4"], changes["3"]})
+			} else {
+				body, err = json.Marshal(changes[strings.TrimPrefix(u.Path, "a/changes/")])
+			}
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+	testData := []struct {
+		name   string
+		change string
+		force  bool
+		lead   int
+		topic  int
+	}{
+		{name: "submittable", change: "1", lead: 1},
+		{name: "not submittable", change: "2"},
+		{name: "forced", change: "2", force: true, lead: 2},
+		{name: "partial topic", change: "3"},
+		{name: "forced partial topic", change: "3", force: true, lead: 3, topic: 1},
+	}
+
+	for _, test := range testData {
+		// Act
+		result, err := services.GerritSubmitter.SubmitInfo(test.change, test.force)
+
+		// Assert
+		if test.lead == 0 {
+			if err == nil {
+				t.Errorf("%s: expected the change to be refused", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: resulting error expected to be nil, received: %v", test.name, err)
+			continue
+		}
+		if result.ChangeNumber != test.lead || len(result.TopicChanges) != test.topic {
+			t.Errorf("%s: expected change %d leading %d topic changes, got: %d leading %d", test.name, test.lead,
+				test.topic, result.ChangeNumber, len(result.TopicChanges))
+		}
+	}
+}
+
+func TestGerritSubmissionServiceImpl_CallMergePipelineTopic(t *testing.T) {
+	// Arrange
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			return []byte(")]}'[]"), nil
+		},
+		getRepoRootFn: func() string {
+			return "https://website.com/"
+		},
+	}
+	var payload types.TektonMergePayload
+	services.EventListenerServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			return []byte{}, json.Unmarshal(content, &payload)
+		},
+	}
+	revisions := map[string]types.Revision{"rev": {Number: 3}}
+	testingPatchset := &types.PendingSubmitInfo{
+		Project:         "ProjectA",
+		ChangeNumber:    1,
+		Topic:           "complete",
+		CurrentRevision: "rev",
+		Revisions:       revisions,
+		TopicChanges: []*types.PendingSubmitInfo{{
+			Project:         "ProjectB",
+			ChangeNumber:    2,
+			Topic:           "complete",
+			CurrentRevision: "rev",
+			Revisions:       revisions,
+		}},
+	}
+
+	// Act
+	err := services.GerritSubmitter.CallMergePipeline(testingPatchset)
+
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	if payload.Topic != "complete" || len(payload.Changes) != 2 {
+		t.Fatalf("expected a single event for the whole topic, got: %+v", payload)
+	}
+	if payload.Changes[1].Project != "ProjectB" || payload.Changes[1].ChangeNumber != "2" {
+		t.Errorf("unexpected second change in merge event: %+v", payload.Changes[1])
+	}
+}
+
+func TestGerritSubmissionServiceImpl_PendingSubmitRelationChain(t *testing.T) {
+	// Arrange
+	chains := map[string][]types.RelatedChangeAndCommitInfo{
+		// Change 2 depends on change 1, which depends on the merged change 9.
+		"1": {{ChangeNumber: 2, Status: "NEW"}, {ChangeNumber: 1, Status: "NEW"}, {ChangeNumber: 9, Status: "MERGED"}},
+		"2": {{ChangeNumber: 2, Status: "NEW"}, {ChangeNumber: 1, Status: "NEW"}, {ChangeNumber: 9, Status: "MERGED"}},
+		// Change 3 depends on change 4, which is not submittable.
+		"3": {{ChangeNumber: 3, Status: "NEW"}, {ChangeNumber: 4, Status: "NEW"}},
+	}
+	serverMock := serverServiceMock{}
+	serverMock.getURLFn = func() url.URL {
+		mockedURL, _ := url.Parse("https://website.com")
+		return *mockedURL
+	}
+	serverMock.getFn = func(u *url.URL) ([]byte, error) {
+		for change, chain := range chains {
+			if strings.Contains(u.Path, "changes/"+change+"/") {
+				body, _ := relatedChangesResponse(u, chain...)
+				return body, nil
+			}
+		}
+		out := []*types.PendingSubmitInfo{
+			{ID: "ID-2", ChangeNumber: 2, Mergeable: true, Subittable: true},
+			{ID: "ID-3", ChangeNumber: 3, Mergeable: true, Subittable: true},
+			{ID: "ID-1", ChangeNumber: 1, Mergeable: true, Subittable: true},
+		}
+		body, err := json.Marshal(&out)
+		body = append([]byte(")]}'"), body...)
+		return body, err
+	}
+
+	services.GerritServer = serverMock
+
+	// Act
+	result, err := services.GerritSubmitter.PendingSubmit()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received error from PendingSubmit function: %v", err)
+	}
+	if len(result) != 2 || result[0].ChangeNumber != 1 || result[1].ChangeNumber != 2 {
+		var numbers []int
+		for _, r := range result {
+			numbers = append(numbers, r.ChangeNumber)
+		}
+		t.Errorf("expected changes [1 2] in parent first order, got: %v", numbers)
+	}
+}
+
+func TestGerritSubmissionServiceImpl_PendingSubmitHashtags(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Submit.Hashtags = types.HashtagPolicy{
+		Require:  []string{"jarvis-merge"},
+		Exclude:  []string{"do-not-merge"},
+		Priority: []string{"hotfix"},
+	}
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	serverMock := serverServiceMock{}
+	serverMock.getURLFn = func() url.URL {
+		mockedURL, _ := url.Parse("https://website.com")
+		return *mockedURL
+	}
+	serverMock.getFn = func(u *url.URL) ([]byte, error) {
+		if body, ok := relatedChangesResponse(u); ok {
+			return body, nil
+		}
+		out := []*types.PendingSubmitInfo{
+			{ID: "ID-1", ChangeNumber: 1, Hashtags: []string{"jarvis-merge"}},
+			{ID: "ID-2", ChangeNumber: 2, Hashtags: []string{"jarvis-merge", "do-not-merge"}},
+			{ID: "ID-3", ChangeNumber: 3},
+			{ID: "ID-4", ChangeNumber: 4, Hashtags: []string{"jarvis-merge", "hotfix"}},
+		}
+		for _, o := range out {
+			o.Mergeable = true
+			o.Subittable = true
+		}
+		body, err := json.Marshal(&out)
+		body = append([]byte(")]}'"), body...)
+		return body, err
+	}
+
+	services.GerritServer = serverMock
+
+	// Act
+	result, err := services.GerritSubmitter.PendingSubmit()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received error from PendingSubmit function: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("result length is expected to be 2, got: %d", len(result))
+	}
+	if result[0].ChangeNumber != 4 || !result[0].Priority {
+		t.Errorf("expected the hotfix change 4 first with priority, got: %+v", result[0])
+	}
+	if result[1].ChangeNumber != 1 || result[1].Priority {
+		t.Errorf("expected change 1 second without priority, got: %+v", result[1])
+	}
+}
+
+func TestGerritSubmissionServiceImpl_CallMergePipelinePayload(t *testing.T) {
+	// Arrange
+	services.GerritServer = serverServiceMock{
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			return []byte(")]}'[]"), nil
+		},
+		getRepoRootFn: func() string {
+			return "https://website.com/"
+		},
+	}
+	var payload types.TektonMergePayload
+	services.EventListenerServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			return []byte{}, json.Unmarshal(content, &payload)
+		},
+	}
+	testingPatchset := &types.PendingSubmitInfo{
+		Project:         "MyProject",
+		Branch:          "master",
+		ChangeNumber:    10,
+		ChangeID:        "I8473b95934b5732ac55d26311a706c9c2bde9940",
+		SubmitType:      "MERGE_IF_NECESSARY",
+		Hashtags:        []string{"jarvis-merge"},
+		Owner:           types.AccountInfo{AccountID: 1000000, Name: "Jarvis", Username: "jarvis"},
+		CurrentRevision: "184ebe53805e102605d11f6b143486d15c23a09c",
+		Revisions: map[string]types.Revision{
+			"184ebe53805e102605d11f6b143486d15c23a09c": {Number: 2, Ref: "refs/changes/10/10/2"},
+		},
+	}
+
+	// Act
+	err := services.GerritSubmitter.CallMergePipeline(testingPatchset)
+
+	// Assert
+	if err != nil {
+		t.Errorf("resulting error expected to be nil, received: %v", err)
+	}
+	expected := types.TektonMergePayload{
+		Version:        types.MergePayloadVersion,
+		RepoRoot:       "https://website.com/",
+		Project:        "MyProject",
+		Branch:         "master",
+		ChangeNumber:   "10",
+		ChangeID:       "I8473b95934b5732ac55d26311a706c9c2bde9940",
+		PatchSetNumber: "2",
+		Revision:       "184ebe53805e102605d11f6b143486d15c23a09c",
+		Ref:            "refs/changes/10/10/2",
+		SubmitType:     "MERGE_IF_NECESSARY",
+		Owner:          types.TektonAccount{AccountID: 1000000, Name: "Jarvis", Username: "jarvis"},
+		Hashtags:       []string{"jarvis-merge"},
+	}
+	expectedJSON, _ := json.Marshal(&expected)
+	actualJSON, _ := json.Marshal(&payload)
+	if string(expectedJSON) != string(actualJSON) {
+		t.Errorf("expected payload %s, got: %s", expectedJSON, actualJSON)
+	}
+}
+
+func TestGerritSubmissionServiceImpl_ExecuteSubmitRebase(t *testing.T) {
+	testData := []struct {
+		name       string
+		submitType string
+		parent     string
+		stacked    bool
+		rebase     string
+		expected   string
+		events     string
+	}{
+		// The checks of the rebased patchset are picked up as pending checks, it is submitted in a later round.
+		{name: "behind", submitType: "REBASE_IF_NECESSARY", parent: "old", expected: "a/changes/10/rebase"},
+		{name: "up to date", submitType: "REBASE_ALWAYS", parent: "tip", expected: "lock", events: "merge"},
+		{name: "fast forward", submitType: "FAST_FORWARD_ONLY", parent: "old", expected: "lock", events: "merge"},
+		{name: "stacked", submitType: "REBASE_IF_NECESSARY", parent: "parent", stacked: true, expected: "lock",
+			events: "merge"},
+		{name: "already up to date", submitType: "REBASE_IF_NECESSARY", parent: "old",
+			rebase: "Change is already up to date.", expected: "a/changes/10/rebase lock", events: "merge"},
+		{name: "conflict", submitType: "REBASE_IF_NECESSARY", parent: "old",
+			rebase:   "The change could not be rebased due to a conflict during merge.",
+			expected: "a/changes/10/rebase a/changes/10/revisions/1/review"},
+	}
+
+	for _, test := range testData {
+		// Arrange
+		services.GerritSubmitter = &services.GerritSubmissionServiceImpl{}
+		var gerritPosts, events []string
+		rebase := test.rebase
+		stacked := test.stacked
+		services.GerritServer = serverServiceMock{
+			getURLFn: func() url.URL {
+				return url.URL{}
+			},
+			getFn: func(u *url.URL) ([]byte, error) {
+				if strings.Contains(u.EscapedPath(), "projects/my%2Fproject/branches/master") {
+					return []byte(`)]}'{"ref": "refs/heads/master", "revision": "tip"}`), nil
+				}
+				if stacked {
+					if body, ok := relatedChangesResponse(u,
+						types.RelatedChangeAndCommitInfo{ChangeNumber: 10, Status: "NEW"},
+						types.RelatedChangeAndCommitInfo{ChangeNumber: 9, Status: "NEW"}); ok {
+						return body, nil
+					}
+				}
+				return []byte(`)]}'{"changes": []}`), nil
+			},
+			getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+				return []byte(")]}'[]"), nil
+			},
+			postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+				if strings.HasSuffix(pathing, "/review/") {
+					pathing = "lock"
+				}
+				gerritPosts = append(gerritPosts, pathing)
+				if strings.HasSuffix(pathing, "/rebase") && rebase != "" {
+					return nil, &services.StatusError{StatusCode: 409, Body: rebase}
+				}
+				return []byte(")]}'{}"), nil
+			},
+			getRepoRootFn: func() string {
+				return "https://website.com/"
+			},
+		}
+		services.EventListenerServer = serverServiceMock{
+			postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+				events = append(events, headers[1].Value)
+				return []byte{}, nil
+			},
+		}
+		testingPatchset := &types.PendingSubmitInfo{
+			Project:         "my/project",
+			Branch:          "master",
+			ChangeNumber:    10,
+			ChangeID:        "realisticValue",
+			SubmitType:      test.submitType,
+			CurrentRevision: "current",
+			Revisions: map[string]types.Revision{"current": {
+				Number: 1,
+				Commit: types.CommitInfo{Parents: []types.CommitInfo{{Commit: test.parent}}},
+			}},
+		}
+
+		// Act
+		err := services.GerritSubmitter.ExecuteSubmit(testingPatchset)
+		// A change that could not be rebased is skipped in the following rounds.
+		if err == nil && test.name == "conflict" {
+			err = services.GerritSubmitter.ExecuteSubmit(testingPatchset)
+		}
+
+		// Assert
+		if err != nil {
+			t.Errorf("%s: resulting error expected to be nil, received: %v", test.name, err)
+		}
+		if strings.Join(gerritPosts, " ") != test.expected {
+			t.Errorf("%s: expected requests %q, got: %v", test.name, test.expected, gerritPosts)
+		}
+		if strings.Join(events, " ") != test.events {
+			t.Errorf("%s: expected events %q, got: %v", test.name, test.events, events)
+		}
+	}
+	services.GerritSubmitter = &services.GerritSubmissionServiceImpl{}
+}
+
+func TestGerritSubmissionServiceImpl_PendingSubmitRebased(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	defer func() { services.Config = services.DefaultConfig() }()
+	submitter := &services.GerritSubmissionServiceImpl{}
+	services.GerritSubmitter = submitter
+	defer func() { services.GerritSubmitter = &services.GerritSubmissionServiceImpl{} }()
+
+	revision := "current"
+	var checks []*types.CheckInfo
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			if strings.Contains(u.EscapedPath(), "projects/my%2Fproject/branches/master") {
+				return []byte(`)]}'{"ref": "refs/heads/master", "revision": "tip"}`), nil
+			}
+			if body, ok := relatedChangesResponse(u); ok {
+				return body, nil
+			}
+			body, err := json.Marshal([]*types.PendingSubmitInfo{{ID: "ID-10", Project: "my/project",
+				Branch: "master", ChangeNumber: 10, CurrentRevision: revision, Mergeable: true, Subittable: true}})
+			return append([]byte(")]}'"), body...), err
+		},
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			var body []byte
+			var err error
+			switch pathing {
+			case "a/plugins/checks/checkers/":
+				body, err = json.Marshal([]*types.CheckerInfo{
+					{UUID: "jarvis:lint", Repository: "my/project", Status: "ENABLED"}})
+			case "a/changes/10/revisions/rebased/checks":
+				body, err = json.Marshal(checks)
+			default:
+				t.Errorf("unexpected request for %s", pathing)
+			}
+			return append([]byte(")]}'"), body...), err
+		},
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			if pathing != "a/changes/10/rebase" {
+				t.Errorf("expected only a rebase, got: %s", pathing)
+			}
+			return []byte(")]}'{}"), nil
+		},
+	}
+
+	// Act
+	before, err := submitter.PendingSubmit()
+	if err != nil {
+		t.Fatalf("Received error from PendingSubmit function: %v", err)
+	}
+	err = submitter.ExecuteSubmit(&types.PendingSubmitInfo{
+		Project:         "my/project",
+		Branch:          "master",
+		ChangeNumber:    10,
+		SubmitType:      "REBASE_IF_NECESSARY",
+		CurrentRevision: revision,
+		Revisions: map[string]types.Revision{revision: {
+			Number: 1,
+			Commit: types.CommitInfo{Parents: []types.CommitInfo{{Commit: "old"}}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Received error from ExecuteSubmit function: %v", err)
+	}
+	revision = "rebased"
+	pending, err := submitter.PendingSubmit()
+	if err != nil {
+		t.Fatalf("Received error from PendingSubmit function: %v", err)
+	}
+	checks = []*types.CheckInfo{{CheckerUUID: "jarvis:lint", State: "SUCCESSFUL"}}
+	passed, err := submitter.PendingSubmit()
+	if err != nil {
+		t.Fatalf("Received error from PendingSubmit function: %v", err)
+	}
+
+	// Assert
+	if len(before) != 1 {
+		t.Errorf("expected the change to be submitted without checks before the rebase, got: %d changes", len(before))
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected the rebased change to wait for its checks, got: %d changes", len(pending))
+	}
+	if len(passed) != 1 {
+		t.Errorf("expected the rebased change to be submitted once its checks passed, got: %d changes", len(passed))
+	}
+}
+
+func TestGerritSubmissionServiceImpl_PendingSubmitRequireChecks(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Submit.RequireChecks = true
+	defer func() { services.Config = services.DefaultConfig() }()
+
+	checks := map[string][]*types.CheckInfo{
+		"a/changes/1/revisions/rev1/checks": {
+			{CheckerUUID: "jarvis:unit", State: "SUCCESSFUL"},
+			{CheckerUUID: "jarvis:lint", State: "NOT_RELEVANT"},
+		},
+		"a/changes/2/revisions/rev2/checks": {
+			{CheckerUUID: "jarvis:unit", State: "SUCCESSFUL"},
+			{CheckerUUID: "jarvis:lint", State: "FAILED"},
+		},
+		// The lint check was never started.
+		"a/changes/3/revisions/rev3/checks": {
+			{CheckerUUID: "jarvis:unit", State: "SUCCESSFUL"},
+		},
+	}
+	serverMock := serverServiceMock{}
+	serverMock.getURLFn = func() url.URL {
+		mockedURL, _ := url.Parse("https://website.com")
+		return *mockedURL
+	}
+	checkerLists := 0
+	serverMock.getPathFn = func(pathing string, headers []types.Header) ([]byte, error) {
+		var body []byte
+		var err error
+		if pathing == "a/plugins/checks/checkers/" {
+			checkerLists++
+			body, err = json.Marshal([]*types.CheckerInfo{
+				{UUID: "jarvis:unit", Repository: "MyProject", Status: "ENABLED"},
+				{UUID: "jarvis:lint", Repository: "MyProject", Status: "ENABLED"},
+				{UUID: "jarvis:other", Repository: "OtherProject", Status: "ENABLED"},
+				{UUID: "other:unit", Repository: "MyProject", Status: "ENABLED"},
+			})
+		} else {
+			body, err = json.Marshal(checks[pathing])
+		}
+		return append([]byte(")]}'"), body...), err
+	}
+	serverMock.getFn = func(u *url.URL) ([]byte, error) {
+		if body, ok := relatedChangesResponse(u); ok {
+			return body, nil
+		}
+		var out []*types.PendingSubmitInfo
+		for i := 1; i <= 3; i++ {
+			out = append(out, &types.PendingSubmitInfo{
+				ID:              fmt.Sprintf("ID-%d", i),
+				Project:         "MyProject",
+				ChangeNumber:    i,
+				CurrentRevision: fmt.Sprintf("rev%d", i),
+				Mergeable:       true,
+				Subittable:      true,
+			})
+		}
+		body, err := json.Marshal(&out)
+		body = append([]byte(")]}'"), body...)
+		return body, err
+	}
+
+	services.GerritServer = serverMock
+
+	// Act
+	result, err := services.GerritSubmitter.PendingSubmit()
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Received error from PendingSubmit function: %v", err)
+	}
+	if len(result) != 1 || result[0].ChangeNumber != 1 {
+		t.Errorf("expected only change 1 with passed checks, got: %d changes", len(result))
+	}
+	if checkerLists != 1 {
+		t.Errorf("expected the checkers to be listed once per round, got: %d", checkerLists)
+	}
+}
+
+func TestGerritSubmissionServiceImpl_Explain(t *testing.T) {
+	// Arrange
+	changes := map[string]*types.PendingSubmitInfo{
+		"3": {ID: "ID-3", ChangeNumber: 3, Project: "MyProject", Status: "NEW", Mergeable: true, Subittable: true,
+			Labels: map[string]types.Label{"Code-Review": {Approved: types.Approval{AccountID: 1000001}}}},
+		"4": {ID: "ID-4", ChangeNumber: 4, Project: "MyProject", Status: "NEW", Mergeable: true},
+	}
+	var queries []string
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			if body, ok := relatedChangesResponse(u, types.RelatedChangeAndCommitInfo{ChangeNumber: 3, Status: "NEW"},
+				types.RelatedChangeAndCommitInfo{ChangeNumber: 4, Status: "NEW"}); ok {
+				return body, nil
+			}
+			var out interface{}
+			if q := u.Query().Get("q"); q != "" {
+				queries = append(queries, q)
+				out = []*types.PendingSubmitInfo{changes["3"]}
+			} else {
+				out = changes[path.Base(u.Path)]
+			}
+			body, err := json.Marshal(out)
+			return append([]byte(")]}'"), body...), err
+		},
+	}
+
+	// Act
+	explanation, err := services.GerritSubmitter.Explain("3")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("resulting error expected to be nil, received: %v", err)
+	}
+	if explanation.Submittable {
+		t.Errorf("expected the change not to be submittable while its ancestor is not")
+	}
+	results := map[string]types.ConditionResult{}
+	var names []string
+	for _, condition := range explanation.Conditions {
+		results[condition.Name] = condition
+		names = append(names, condition.Name)
+	}
+	expected := "query open mergeable submittable unlocked hashtags checks freeze ancestors topic"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("expected conditions %q, got: %q", expected, strings.Join(names, " "))
+	}
+	for _, name := range names {
+		if passed := results[name].Passed; passed != (name != "ancestors") {
+			t.Errorf("%s: expected passed: %t, got: %+v", name, !passed, results[name])
+		}
+	}
+	if !strings.Contains(results["ancestors"].Details, "4 fails submittable") {
+		t.Errorf("expected the failing ancestor to be named, got: %q", results["ancestors"].Details)
+	}
+	if len(queries) != 1 || !strings.HasPrefix(queries[0], "change:3 (status:open)") {
+		t.Errorf("expected the change to be matched against the submit query, got: %v", queries)
+	}
+	if explanation.Labels["Code-Review"].Approved.AccountID != 1000001 {
+		t.Errorf("expected the label data, got: %+v", explanation.Labels)
+	}
+}
@@ -1,14 +1,17 @@
 package services_test
 
 import (
+	"encoding/json"
 	"github.com/att-comdev/jarvis-connector/services"
 	"github.com/att-comdev/jarvis-connector/types"
 	"net/url"
+	"strings"
 )
 
 type serverServiceMock struct {
 	postPathFn    func(pathing string, headers []types.Header, content []byte) ([]byte, error)
 	getPathFn     func(pathing string, headers []types.Header) ([]byte, error)
+	deletePathFn  func(pathing string, headers []types.Header) ([]byte, error)
 	getFn         func(u *url.URL) ([]byte, error)
 	initFn        func(url url.URL, authenticator services.Authenticator, testPath string)
 	getURLFn      func() url.URL
@@ -23,6 +26,10 @@ func (s serverServiceMock) PostPath(pathing string, headers []types.Header, cont
 	return s.postPathFn(pathing, headers, content)
 }
 
+func (s serverServiceMock) DeletePath(pathing string, headers []types.Header) ([]byte, error) {
+	return s.deletePathFn(pathing, headers)
+}
+
 func (s serverServiceMock) Get(u *url.URL) ([]byte, error) {
 	return s.getFn(u)
 }
@@ -37,4 +44,14 @@ func (s serverServiceMock) GetURL() url.URL {
 
 func (s serverServiceMock) GetRepoRoot() string {
 	return s.getRepoRootFn()
-}
\ No newline at end of file
+}
+
+// relatedChangesResponse answers a request for the relation chain of a change with the given chain. It reports
+// whether the request was one for related changes.
+func relatedChangesResponse(u *url.URL, related ...types.RelatedChangeAndCommitInfo) ([]byte, bool) {
+	if !strings.HasSuffix(u.Path, "/related") {
+		return nil, false
+	}
+	body, _ := json.Marshal(&types.RelatedChangesInfo{Changes: related})
+	return append([]byte(")]}'"), body...), true
+}
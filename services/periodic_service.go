@@ -0,0 +1,154 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+var (
+	Periodic periodicService = &PeriodicServiceImpl{runs: map[string]*types.PeriodicRun{}}
+)
+
+type periodicService interface {
+	DuePeriodic(now time.Time) []*types.PeriodicJob
+	ExecutePeriodic(job *types.PeriodicJob) error
+	ReportPeriodicResult(result *types.PeriodicResult) error
+	PeriodicRuns() []*types.PeriodicRun
+}
+
+type PeriodicServiceImpl struct {
+	mu sync.Mutex
+	// runs holds the latest run of each job, by job name.
+	runs map[string]*types.PeriodicRun
+}
+
+// ValidatePeriodicJobs checks that every periodic job is well formed and has a unique name.
+func ValidatePeriodicJobs(jobs []types.PeriodicJob) error {
+	names := map[string]bool{}
+	for i, job := range jobs {
+		if job.Name == "" || job.Repository == "" || job.Branch == "" {
+			return fmt.Errorf("periodic job %d must set name, repository and branch", i)
+		}
+		if names[job.Name] {
+			return fmt.Errorf("periodic job %q is defined twice", job.Name)
+		}
+		names[job.Name] = true
+		if _, err := ParseCron(job.Schedule); err != nil {
+			return fmt.Errorf("periodic job %q: %v", job.Name, err)
+		}
+	}
+	return nil
+}
+
+// DuePeriodic returns the configured jobs whose schedule fires in the minute of now.
+func (p *PeriodicServiceImpl) DuePeriodic(now time.Time) []*types.PeriodicJob {
+	var due []*types.PeriodicJob
+	for i := range Config.Periodic {
+		job := &Config.Periodic[i]
+		schedule, err := ParseCron(job.Schedule)
+		if err != nil {
+			log.Printf("periodic job %q: %v", job.Name, err)
+			continue
+		}
+		if schedule.Matches(now) {
+			due = append(due, job)
+		}
+	}
+	return due
+}
+
+// ExecutePeriodic sends a "periodic" event for the current tip of the job's branch.
+func (p *PeriodicServiceImpl) ExecutePeriodic(job *types.PeriodicJob) error {
+	branch, err := GerritChanges.GetBranch(job.Repository, job.Branch)
+	if err != nil {
+		return err
+	}
+
+	data := types.TektonPeriodicPayload{
+		Version:  types.PeriodicPayloadVersion,
+		RepoRoot: GerritServer.GetRepoRoot(),
+		Name:     job.Name,
+		Project:  job.Repository,
+		Branch:   job.Branch,
+		Revision: branch.Revision,
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	headers := []types.Header{{
+		Key:   "Content-Type",
+		Value: "application/json",
+	}, {
+		Key:   "X-Jarvis",
+		Value: "periodic",
+	}}
+	run := &types.PeriodicRun{
+		Name:     job.Name,
+		Project:  job.Repository,
+		Branch:   job.Branch,
+		Revision: branch.Revision,
+		Status:   PipelineRunningString,
+		Started:  types.Timestamp(time.Now()),
+	}
+	if _, err := EventListenerServer.PostPath("", headers, body); err != nil {
+		run.Status = PipelineFailureString
+		run.Message = err.Error()
+	}
+
+	p.mu.Lock()
+	p.runs[job.Name] = run
+	p.mu.Unlock()
+
+	if run.Status == PipelineFailureString {
+		return fmt.Errorf("sending periodic event for %q: %s", job.Name, run.Message)
+	}
+	log.Printf("sent periodic event for %q at %s", job.Name, branch.Revision)
+	return nil
+}
+
+// ReportPeriodicResult records the outcome of a periodic pipeline on its latest run.
+func (p *PeriodicServiceImpl) ReportPeriodicResult(result *types.PeriodicResult) error {
+	if result.Status != PipelineSuccessString && result.Status != PipelineFailureString {
+		return fmt.Errorf("unknown periodic result status %q", result.Status)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	run, ok := p.runs[result.Name]
+	if !ok {
+		return fmt.Errorf("no run of periodic job %q", result.Name)
+	}
+	if result.Revision != "" && result.Revision != run.Revision {
+		return fmt.Errorf("periodic job %q last ran at %s, not %s", result.Name, run.Revision, result.Revision)
+	}
+
+	run.Status = result.Status
+	run.Message = result.Message
+	run.URL = result.URL
+	run.Finished = types.Timestamp(time.Now())
+	return nil
+}
+
+// PeriodicRuns returns the latest run of every job, ordered by job name.
+func (p *PeriodicServiceImpl) PeriodicRuns() []*types.PeriodicRun {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	runs := make([]*types.PeriodicRun, 0, len(p.runs))
+	for _, run := range p.runs {
+		r := *run
+		runs = append(runs, &r)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].Name < runs[j].Name
+	})
+	return runs
+}
@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestPeriodicServiceImpl_DuePeriodic(t *testing.T) {
+	// Arrange
+	services.Config = services.DefaultConfig()
+	services.Config.Periodic = []types.PeriodicJob{
+		{Name: "nightly", Repository: "MyProject", Branch: "master", Schedule: "0 2 * * *"},
+		{Name: "weekly", Repository: "MyProject", Branch: "master", Schedule: "0 2 * * 0"},
+	}
+	defer func() { services.Config = services.DefaultConfig() }()
+	friday, _ := time.Parse(time.RFC3339, "2023-03-10T02:00:00Z")
+
+	// Act
+	due := services.Periodic.DuePeriodic(friday)
+
+	// Assert
+	if len(due) != 1 || due[0].Name != "nightly" {
+		t.Errorf("expected only the nightly job to be due, got: %v", due)
+	}
+}
+
+func TestPeriodicServiceImpl_ExecutePeriodic(t *testing.T) {
+	// Arrange
+	var branchPath string
+	services.GerritServer = serverServiceMock{
+		getURLFn: func() url.URL {
+			return url.URL{}
+		},
+		getFn: func(u *url.URL) ([]byte, error) {
+			branchPath = u.EscapedPath()
+			return []byte(`)]}'{"ref": "refs/heads/stable/2023.1", "revision": "tip"}`), nil
+		},
+		getRepoRootFn: func() string {
+			return "https://website.com/"
+		},
+	}
+	var event string
+	var payload types.TektonPeriodicPayload
+	services.EventListenerServer = serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			event = headers[1].Value
+			return []byte{}, json.Unmarshal(content, &payload)
+		},
+	}
+	job := &types.PeriodicJob{Name: "nightly-stable", Repository: "airship/treasuremap", Branch: "stable/2023.1"}
+
+	// Act
+	err := services.Periodic.ExecutePeriodic(job)
+	var started string
+	for _, r := range services.Periodic.PeriodicRuns() {
+		if r.Name == job.Name {
+			started = r.Status
+		}
+	}
+	reportErr := services.Periodic.ReportPeriodicResult(&types.PeriodicResult{
+		Name:     "nightly-stable",
+		Revision: "tip",
+		Status:   services.PipelineFailureString,
+		URL:      "https://tekton.local/run/1",
+	})
+
+	// Assert
+	if err != nil || reportErr != nil {
+		t.Fatalf("resulting errors expected to be nil, received: %v, %v", err, reportErr)
+	}
+	if branchPath != "a/projects/airship%2Ftreasuremap/branches/stable%2F2023.1" {
+		t.Errorf("unexpected branch request: %q", branchPath)
+	}
+	if event != "periodic" || payload.Revision != "tip" || payload.Branch != "stable/2023.1" {
+		t.Errorf("unexpected event %q with payload: %+v", event, payload)
+	}
+	if started != services.PipelineRunningString {
+		t.Errorf("expected the run to be %s until its result is reported, got: %q", services.PipelineRunningString,
+			started)
+	}
+	var run *types.PeriodicRun
+	for _, r := range services.Periodic.PeriodicRuns() {
+		if r.Name == job.Name {
+			run = r
+		}
+	}
+	if run == nil || run.Status != services.PipelineFailureString || run.URL != "https://tekton.local/run/1" {
+		t.Errorf("expected the failure to be recorded on the run, got: %+v", run)
+	}
+}
+
+func TestValidatePeriodicJobs(t *testing.T) {
+	invalid := [][]types.PeriodicJob{
+		{{Repository: "MyProject", Branch: "master", Schedule: "0 2 * * *"}},
+		{{Name: "nightly", Repository: "MyProject", Branch: "master", Schedule: "0 2 * *"}},
+		{
+			{Name: "nightly", Repository: "MyProject", Branch: "master", Schedule: "0 2 * * *"},
+			{Name: "nightly", Repository: "OtherProject", Branch: "master", Schedule: "0 2 * * *"},
+		},
+	}
+	for _, jobs := range invalid {
+		if err := services.ValidatePeriodicJobs(jobs); err == nil {
+			t.Errorf("expected periodic jobs %+v to be invalid", jobs)
+		}
+	}
+	if err := services.ValidatePeriodicJobs(nil); err != nil {
+		t.Errorf("expected no periodic jobs to be valid, got: %v", err)
+	}
+}
@@ -21,12 +21,25 @@ var (
 type serverService interface {
 	GetPath(pathing string, headers []types.Header) ([]byte, error)
 	PostPath(pathing string, headers []types.Header, content []byte) ([]byte, error)
+	DeletePath(pathing string, headers []types.Header) ([]byte, error)
 	Get(u *url.URL) ([]byte, error)
 	Init(url url.URL, authenticator Authenticator, testPath string)
 	GetURL() url.URL
 	GetRepoRoot() string
 }
 
+// StatusError is returned when the server answers a request with a non 2xx status code.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: status %d", e.Method, e.URL, e.StatusCode)
+}
+
 type ServerImpl struct {
 	UserAgent string
 	URL       url.URL
@@ -84,12 +97,7 @@ func (service *ServerImpl) GetPath(pathing string, headers []types.Header) ([]by
 	if err != nil {
 		return nil, err
 	}
-	if rep.StatusCode/100 != 2 {
-		return nil, fmt.Errorf("GetPath %s: status %d", u.String(), rep.StatusCode)
-	}
-
-	defer rep.Body.Close()
-	return ioutil.ReadAll(rep.Body)
+	return readResponse("GetPath", u.String(), rep)
 }
 
 // PostPath posts the given data onto a path.
@@ -111,12 +119,26 @@ func (service *ServerImpl) PostPath(pathing string, headers []types.Header, cont
 	if err != nil {
 		return nil, err
 	}
-	if rep.StatusCode/100 != 2 {
-		return nil, fmt.Errorf("PostPath %s: status %d", u.String(), rep.StatusCode)
+	return readResponse("PostPath", u.String(), rep)
+}
+
+// DeletePath runs a DELETE request on the given path.
+func (service *ServerImpl) DeletePath(pathing string, headers []types.Header) ([]byte, error) {
+	u := service.URL
+	u.Path = path.Join(u.Path, pathing)
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, header := range headers {
+		req.Header.Set(header.Key, header.Value)
 	}
 
-	defer rep.Body.Close()
-	return ioutil.ReadAll(rep.Body)
+	rep, err := service.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return readResponse("DeletePath", u.String(), rep)
 }
 
 // Get runs a HTTP GET request on the given URL.
@@ -129,12 +151,22 @@ func (service *ServerImpl) Get(u *url.URL) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if rep.StatusCode/100 != 2 {
-		return nil, fmt.Errorf("Get %s: status %d", u.String(), rep.StatusCode)
-	}
+	return readResponse("Get", u.String(), rep)
+}
 
+// readResponse returns the body of a successful response, or a StatusError otherwise.
+func readResponse(method, u string, rep *http.Response) ([]byte, error) {
 	defer rep.Body.Close()
-	return ioutil.ReadAll(rep.Body)
+	content, err := ioutil.ReadAll(rep.Body)
+	if rep.StatusCode/100 != 2 {
+		return nil, &StatusError{
+			Method:     method,
+			URL:        u,
+			StatusCode: rep.StatusCode,
+			Body:       strings.TrimSpace(string(content)),
+		}
+	}
+	return content, err
 }
 
 // do runs a HTTP request against the remote server.
@@ -155,3 +187,56 @@ func (service *ServerImpl) do(req *http.Request) (*http.Response, error) {
 	}
 	return service.Client.Do(req)
 }
+
+// TracingServer wraps a server, logging every request made through it and its outcome.
+type TracingServer struct {
+	serverService
+	// Name identifies the server in the log.
+	Name string
+}
+
+// NewTracingServer returns server wrapped in a TracingServer.
+func NewTracingServer(name string, server serverService) *TracingServer {
+	return &TracingServer{serverService: server, Name: name}
+}
+
+// GetPath runs a GetPath on the wrapped server.
+func (t *TracingServer) GetPath(pathing string, headers []types.Header) ([]byte, error) {
+	log.Printf("%s: GET %s", t.Name, pathing)
+	content, err := t.serverService.GetPath(pathing, headers)
+	t.trace(content, err)
+	return content, err
+}
+
+// PostPath runs a PostPath on the wrapped server.
+func (t *TracingServer) PostPath(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+	log.Printf("%s: POST %s %s", t.Name, pathing, content)
+	out, err := t.serverService.PostPath(pathing, headers, content)
+	t.trace(out, err)
+	return out, err
+}
+
+// DeletePath runs a DeletePath on the wrapped server.
+func (t *TracingServer) DeletePath(pathing string, headers []types.Header) ([]byte, error) {
+	log.Printf("%s: DELETE %s", t.Name, pathing)
+	content, err := t.serverService.DeletePath(pathing, headers)
+	t.trace(content, err)
+	return content, err
+}
+
+// Get runs a Get on the wrapped server.
+func (t *TracingServer) Get(u *url.URL) ([]byte, error) {
+	log.Printf("%s: GET %s", t.Name, u)
+	content, err := t.serverService.Get(u)
+	t.trace(content, err)
+	return content, err
+}
+
+// trace logs the outcome of a request.
+func (t *TracingServer) trace(content []byte, err error) {
+	if err != nil {
+		log.Printf("%s: -> %v", t.Name, err)
+		return
+	}
+	log.Printf("%s: -> %d bytes", t.Name, len(content))
+}
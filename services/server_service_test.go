@@ -0,0 +1,47 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/att-comdev/jarvis-connector/services"
+	"github.com/att-comdev/jarvis-connector/types"
+)
+
+func TestTracingServer(t *testing.T) {
+	// Arrange
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	defer log.SetOutput(os.Stderr)
+	server := services.NewTracingServer("gerrit", serverServiceMock{
+		postPathFn: func(pathing string, headers []types.Header, content []byte) ([]byte, error) {
+			return []byte("ok"), nil
+		},
+		getPathFn: func(pathing string, headers []types.Header) ([]byte, error) {
+			return nil, errors.New("not found")
+		},
+	})
+
+	// Act
+	content, postErr := server.PostPath("a/changes/10/review", nil, []byte(`{"message":"hi"}`))
+	_, getErr := server.GetPath("a/changes/11", nil)
+
+	// Assert
+	if postErr != nil || string(content) != "ok" {
+		t.Errorf("expected the wrapped server's response, got: %q, %v", content, postErr)
+	}
+	if getErr == nil {
+		t.Errorf("expected the wrapped server's error")
+	}
+	logged := out.String()
+	for _, expected := range []string{`gerrit: POST a/changes/10/review {"message":"hi"}`, "gerrit: -> 2 bytes",
+		"gerrit: GET a/changes/11", "gerrit: -> not found"} {
+		if !strings.Contains(logged, expected) {
+			t.Errorf("expected %q to be logged, got:\n%s", expected, logged)
+		}
+	}
+}
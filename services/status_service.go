@@ -8,10 +8,27 @@ const (
 	Successful int = 3
 
 	UnsetString      string = "UNSET"
+	NotStartedString string = "NOT_STARTED"
 	IrrelevantString string = "NOT_RELEVANT"
 	RunningString    string = "SCHEDULED"
 	FailString       string = "FAILED"
 	SuccessfulString string = "SCHEDULED"
+
+	// CheckSuccessfulString is the state pipelines set on a check that passed.
+	CheckSuccessfulString string = "SUCCESSFUL"
+
+	// Statuses reported by pipelines through the connector's callback endpoints, and the status of a pipeline that
+	// has not reported yet.
+	PipelineSuccessString string = "SUCCESS"
+	PipelineFailureString string = "FAILURE"
+	PipelineRunningString string = "RUNNING"
+
+	// Statuses of a checker.
+	CheckerEnabledString  string = "ENABLED"
+	CheckerDisabledString string = "DISABLED"
+
+	// BlockingStateNotPassing blocks submission of a change while the checker's check did not pass.
+	BlockingStateNotPassing string = "STATE_NOT_PASSING"
 )
 
 var (
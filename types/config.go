@@ -0,0 +1,127 @@
+package types
+
+// ConnectorConfig holds the deployment wide settings of the connector. It is
+// read from the JSON file given with --config.
+type ConnectorConfig struct {
+	// PageSize is the number of changes requested per page of a change query.
+	PageSize int          `json:"pageSize"`
+	Submit   SubmitConfig `json:"submit"`
+	// Freezes are the windows during which changes are not submitted.
+	Freezes []FreezeWindow `json:"freezes"`
+	// PostMerge controls the events sent once changes are merged.
+	PostMerge PostMergeConfig `json:"postMerge"`
+	// Revert controls reverting changes whose post-merge pipeline failed.
+	Revert RevertConfig `json:"revert"`
+	// Backport controls cherry-picking merged changes onto the branches named by their hashtags.
+	Backport BackportConfig `json:"backport"`
+	// Periodic are the pipelines run on a schedule against the tip of a branch.
+	Periodic []PeriodicJob `json:"periodic"`
+	// Checkers are the checkers that should be registered, as applied by --plan and --apply.
+	Checkers []CheckerSpec `json:"checkers"`
+	// Drift controls comparing the registered checkers with Checkers while the connector runs.
+	Drift DriftConfig `json:"drift"`
+}
+
+// SubmitConfig controls which changes are picked up for submission.
+type SubmitConfig struct {
+	// Query is the Gerrit change query used to find candidate changes.
+	Query string `json:"query"`
+	// Projects overrides Query for the projects they match.
+	Projects []ProjectSubmitConfig `json:"projects"`
+	// Mode is either "pipeline", where the merge pipeline submits the change, or "connector",
+	// where the merge pipeline reports its result and the connector submits the change.
+	Mode string `json:"mode"`
+	// Hashtags limits submission by the hashtags set on a change.
+	Hashtags HashtagPolicy `json:"hashtags"`
+	// RequireChecks only submits changes whose jarvis checks all passed on the current revision.
+	RequireChecks bool `json:"requireChecks"`
+}
+
+// HashtagPolicy decides from its hashtags whether and how a change is submitted.
+type HashtagPolicy struct {
+	// Require, when not empty, only submits changes carrying at least one of these hashtags.
+	Require []string `json:"require"`
+	// Exclude never submits changes carrying any of these hashtags.
+	Exclude []string `json:"exclude"`
+	// Priority submits changes carrying any of these hashtags ahead of the others.
+	Priority []string `json:"priority"`
+}
+
+// ProjectSubmitConfig is a submission query for a single project, or for every
+// project matching a "^"-prefixed regular expression.
+type ProjectSubmitConfig struct {
+	Project string `json:"project"`
+	Query   string `json:"query"`
+}
+
+// FreezeWindow stops submissions to the matching projects and branches while it is active. It is active either
+// between Start and End, or for Duration after each time Schedule fires.
+type FreezeWindow struct {
+	Name string `json:"name"`
+	// Projects and Branches are regular expressions, a window without any applies to every project or branch.
+	Projects []string `json:"projects"`
+	Branches []string `json:"branches"`
+	// Start and End are RFC 3339 timestamps.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Schedule is a five field cron expression in the connector's time zone, Duration a Go duration like "48h".
+	Schedule string `json:"schedule"`
+	Duration string `json:"duration"`
+	// ExemptHashtags lets changes carrying any of these hashtags through the freeze.
+	ExemptHashtags []string `json:"exemptHashtags"`
+	// Message is added to the comment explaining why a change is not submitted.
+	Message string `json:"message"`
+}
+
+// PostMergeConfig controls the "post-merge" events sent to the EventListener when changes are merged.
+type PostMergeConfig struct {
+	Enabled bool `json:"enabled"`
+	// Branches are regular expressions of the branches whose merged changes always get an event. Changes merged by
+	// Jarvis get one on any branch.
+	Branches []string `json:"branches"`
+}
+
+// RevertConfig controls proposing reverts for changes merged by Jarvis whose post-merge pipeline failed.
+type RevertConfig struct {
+	Enabled bool `json:"enabled"`
+	// Hashtag is set on the revert changes.
+	Hashtag string `json:"hashtag"`
+}
+
+// BackportConfig controls cherry-picking merged changes carrying a hashtag such as "backport:stable/2023.1" onto the
+// named branch.
+type BackportConfig struct {
+	Enabled bool `json:"enabled"`
+	// Prefix is the prefix of the hashtags naming a destination branch.
+	Prefix string `json:"prefix"`
+}
+
+// PeriodicJob sends a "periodic" event for the tip of a branch each time its schedule fires.
+type PeriodicJob struct {
+	Name       string `json:"name"`
+	Repository string `json:"repository"`
+	Branch     string `json:"branch"`
+	// Schedule is a five field cron expression in the connector's time zone.
+	Schedule string `json:"schedule"`
+}
+
+// DriftConfig controls the periodic comparison of the registered checkers with the configured ones.
+type DriftConfig struct {
+	Enabled bool `json:"enabled"`
+	// Interval is the time between comparisons, as a Go duration such as "15m".
+	Interval string `json:"interval"`
+	// AutoCorrect creates and updates configured checkers that drifted. Unconfigured checkers are only reported.
+	AutoCorrect bool `json:"autoCorrect"`
+}
+
+// CheckerSpec describes a checker that should be registered. The checker is identified by its repository and
+// prefix, unset fields take the defaults of --register.
+type CheckerSpec struct {
+	Repository  string   `json:"repository"`
+	Prefix      string   `json:"prefix"`
+	Description string   `json:"description"`
+	URL         string   `json:"url"`
+	Query       string   `json:"query"`
+	Blocking    []string `json:"blocking"`
+	Status      string   `json:"status"`
+}
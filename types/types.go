@@ -34,8 +34,8 @@ func (ts *Timestamp) String() string {
 
 var _ = (json.Marshaler)((*Timestamp)(nil))
 
-func (ts *Timestamp) MarshalJSON() ([]byte, error) {
-	t := (*time.Time)(ts)
+func (ts Timestamp) MarshalJSON() ([]byte, error) {
+	t := time.Time(ts)
 	return []byte("\"" + t.Format(timeLayout) + "\""), nil
 }
 
@@ -65,6 +65,31 @@ type CheckerInfo struct {
 	Updated     Timestamp `json:"updated"`
 }
 
+// CheckerFilter selects checkers, empty fields match every checker.
+type CheckerFilter struct {
+	Repository string
+	Status     string
+	Prefix     string
+}
+
+// CheckerDrift is a registered checker that differs from the configuration.
+type CheckerDrift struct {
+	// Kind is one of "missing", "disabled", "changed" or "unmanaged".
+	Kind       string
+	UUID       string
+	Repository string
+	Changes    []string
+}
+
+// CheckerAction is a step of the plan reconciling the registered checkers with the configured ones.
+type CheckerAction struct {
+	// Action is one of "create", "update" or "disable".
+	Action string
+	Input  CheckerInput
+	// Changes describes the fields an update changes.
+	Changes []string
+}
+
 func (info *CheckerInfo) String() string {
 	out, err := json.Marshal(info)
 	if err != nil {
@@ -142,6 +167,7 @@ type CheckInfo struct {
 	CheckerUUID   string    `json:"checker_uuid"`
 	State         string    `json:"state"`
 	Message       string    `json:"message"`
+	URL           string    `json:"url"`
 	Started       Timestamp `json:"started"`
 	Finished      Timestamp `json:"finished"`
 	Created       Timestamp `json:"created"`
@@ -151,13 +177,23 @@ type CheckInfo struct {
 	Blocking      []string  `json:"blocking"`
 }
 
+// CheckPayloadVersion is the version of the TektonListenerPayload schema. Payloads without a version predate it.
+const CheckPayloadVersion = "2"
+
 // TektonListenerPayload to be received by trigger
 type TektonListenerPayload struct {
-	RepoRoot       string `json:"repoRoot"`
-	Project        string `json:"project"`
-	ChangeNumber   string `json:"changeNumber"`
-	PatchSetNumber int    `json:"patchSetNumber"`
-	CheckerUUID    string `json:"checkerUUID"`
+	Version        string        `json:"version"`
+	RepoRoot       string        `json:"repoRoot"`
+	Project        string        `json:"project"`
+	Branch         string        `json:"branch"`
+	ChangeNumber   string        `json:"changeNumber"`
+	PatchSetNumber int           `json:"patchSetNumber"`
+	Revision       string        `json:"revision"`
+	Ref            string        `json:"ref"`
+	Owner          TektonAccount `json:"owner"`
+	Subject        string        `json:"subject"`
+	Files          []string      `json:"files"`
+	CheckerUUID    string        `json:"checkerUUID"`
 }
 
 type Header struct {
@@ -170,6 +206,8 @@ type PendingSubmitInfo struct {
 	Project         string              `json:"project"`
 	Branch          string              `json:"branch"`
 	Hashtags        []string            `json:"hashtags"`
+	Topic           string              `json:"topic"`
+	Owner           AccountInfo         `json:"owner"`
 	ChangeID        string              `json:"change_id"`
 	ChangeNumber    int                 `json:"_number"`
 	Subject         string              `json:"subject"`
@@ -182,21 +220,116 @@ type PendingSubmitInfo struct {
 	CurrentRevision string              `json:"current_revision"`
 	Revisions       map[string]Revision `json:"revisions"`
 	Labels          map[string]Label    `json:"labels"`
+	Messages        []ChangeMessageInfo `json:"messages"`
+	MoreChanges     bool                `json:"_more_changes"`
 	RevisionNumber  int
+
+	// TopicChanges holds the other changes of the topic, which are submitted together with this one.
+	TopicChanges []*PendingSubmitInfo `json:"-"`
+	// Priority is set for changes that are submitted ahead of the others.
+	Priority bool `json:"-"`
+	// Frozen is the freeze window holding the change back, as found while checking its submit conditions.
+	Frozen *FreezeWindow `json:"-"`
+}
+
+// ChangeMessageInfo is a message posted on a change.
+type ChangeMessageInfo struct {
+	ID      string    `json:"id"`
+	Tag     string    `json:"tag"`
+	Message string    `json:"message"`
+	Date    Timestamp `json:"date"`
+}
+
+// SubmitExplanation tells how a change fares against every condition for its submission.
+type SubmitExplanation struct {
+	ChangeNumber int               `json:"changeNumber"`
+	Project      string            `json:"project"`
+	Branch       string            `json:"branch"`
+	Status       string            `json:"status"`
+	Labels       map[string]Label  `json:"labels"`
+	Conditions   []ConditionResult `json:"conditions"`
+	// Submittable is set if every condition passed.
+	Submittable bool `json:"submittable"`
+}
+
+// ConditionResult is the outcome of a submit condition, with the data it was decided on.
+type ConditionResult struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Details string `json:"details"`
+}
+
+// RelatedChangesInfo lists the changes a revision depends on, and the ones depending on it. Descendants come first,
+// then the revision itself, then its ancestors.
+type RelatedChangesInfo struct {
+	Changes []RelatedChangeAndCommitInfo `json:"changes"`
+}
+
+type RelatedChangeAndCommitInfo struct {
+	Project               string     `json:"project"`
+	ChangeID              string     `json:"change_id"`
+	Commit                CommitInfo `json:"commit"`
+	ChangeNumber          int        `json:"_change_number"`
+	RevisionNumber        int        `json:"_revision_number"`
+	CurrentRevisionNumber int        `json:"_current_revision_number"`
+	Status                string     `json:"status"`
+}
+
+type CommitInfo struct {
+	Commit  string       `json:"commit"`
+	Parents []CommitInfo `json:"parents"`
+	Subject string       `json:"subject"`
+}
+
+type ProjectInfo struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Parent string `json:"parent"`
+	State  string `json:"state"`
+}
+
+// RegistrationSummary lists the repositories of a bulk checker registration by outcome.
+type RegistrationSummary struct {
+	Created []string
+	Updated []string
+	Skipped []string
+	Failed  []string
+}
+
+type BranchInfo struct {
+	Ref      string `json:"ref"`
+	Revision string `json:"revision"`
 }
 
 type Revision struct {
-	Kind    string    `json:"kind"`
-	Number  int       `json:"_number"`
-	Created Timestamp `json:"created"`
-	Ref     string    `json:"ref"`
+	Kind    string              `json:"kind"`
+	Number  int                 `json:"_number"`
+	Created Timestamp           `json:"created"`
+	Ref     string              `json:"ref"`
+	Commit  CommitInfo          `json:"commit"`
+	Files   map[string]FileInfo `json:"files"`
+}
+
+type FileInfo struct {
+	Status        string `json:"status"`
+	LinesInserted int    `json:"lines_inserted"`
+	LinesDeleted  int    `json:"lines_deleted"`
 }
 
 type Label struct {
 	Approved Approval `json:"approved"`
+	Rejected Approval `json:"rejected"`
+	Blocking bool     `json:"blocking"`
 	Optional bool     `json:"optional"`
 }
 
+type AccountInfo struct {
+	AccountID int    `json:"_account_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+}
+
 type Approval struct {
 	AccountID int `json:"_account_id"`
 }
@@ -209,10 +342,143 @@ type LabelPayload struct {
 	JarvisLock string `json:"Jarvis-Lock"`
 }
 
+// MergePayloadVersion is the version of the TektonMergePayload schema. Payloads without a version predate it.
+const MergePayloadVersion = "2"
+
+// TektonMergePayload to be received by the merge trigger
 type TektonMergePayload struct {
-	RepoRoot       string `json:"repoRoot"`
+	Version         string        `json:"version"`
+	RepoRoot        string        `json:"repoRoot"`
+	Project         string        `json:"project"`
+	Branch          string        `json:"branch"`
+	ChangeNumber    string        `json:"changeNumber"`
+	ChangeID        string        `json:"changeId"`
+	PatchSetNumber  string        `json:"patchSetNumber"`
+	Revision        string        `json:"revision"`
+	Ref             string        `json:"ref"`
+	SubmitType      string        `json:"submitType"`
+	Owner           TektonAccount `json:"owner"`
+	Hashtags        []string      `json:"hashtags"`
+	CheckerUUID     string        `json:"checkerUUID"`
+	ConnectorSubmit bool          `json:"connectorSubmit"`
+	Priority        bool          `json:"priority"`
+	// Topic and Changes describe every change merged by this event when the change is part of a topic. The merge
+	// result reports Changes back, so that the connector submits or unlocks all of them.
+	Topic   string              `json:"topic,omitempty"`
+	Changes []TektonMergeChange `json:"changes,omitempty"`
+}
+
+// TektonMergeChange is a single change of a topic merge.
+type TektonMergeChange struct {
 	Project        string `json:"project"`
+	Branch         string `json:"branch"`
+	ChangeNumber   string `json:"changeNumber"`
+	ChangeID       string `json:"changeId"`
+	PatchSetNumber string `json:"patchSetNumber"`
+	Revision       string `json:"revision"`
+	Ref            string `json:"ref"`
+}
+
+// TektonAccount describes a Gerrit account in pipeline payloads.
+type TektonAccount struct {
+	AccountID int    `json:"accountId"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+}
+
+// PostMergePayloadVersion is the version of the TektonPostMergePayload schema.
+const PostMergePayloadVersion = "1"
+
+// TektonPostMergePayload to be received by the post-merge trigger
+type TektonPostMergePayload struct {
+	Version        string        `json:"version"`
+	RepoRoot       string        `json:"repoRoot"`
+	Project        string        `json:"project"`
+	Branch         string        `json:"branch"`
+	ChangeNumber   string        `json:"changeNumber"`
+	ChangeID       string        `json:"changeId"`
+	PatchSetNumber string        `json:"patchSetNumber"`
+	Revision       string        `json:"revision"`
+	MergeCommit    string        `json:"mergeCommit"`
+	Owner          TektonAccount `json:"owner"`
+	Subject        string        `json:"subject"`
+	MergedByJarvis bool          `json:"mergedByJarvis"`
+}
+
+// PeriodicPayloadVersion is the version of the TektonPeriodicPayload schema.
+const PeriodicPayloadVersion = "1"
+
+// TektonPeriodicPayload to be received by the periodic trigger
+type TektonPeriodicPayload struct {
+	Version  string `json:"version"`
+	RepoRoot string `json:"repoRoot"`
+	Name     string `json:"name"`
+	Project  string `json:"project"`
+	Branch   string `json:"branch"`
+	Revision string `json:"revision"`
+}
+
+// PeriodicResult is reported back to the connector by a periodic pipeline once it has finished.
+type PeriodicResult struct {
+	Name     string `json:"name"`
+	Revision string `json:"revision"`
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	URL      string `json:"url"`
+}
+
+// PeriodicRun is the latest run of a periodic job.
+type PeriodicRun struct {
+	Name     string    `json:"name"`
+	Project  string    `json:"project"`
+	Branch   string    `json:"branch"`
+	Revision string    `json:"revision"`
+	Status   string    `json:"status"`
+	Message  string    `json:"message"`
+	URL      string    `json:"url"`
+	Started  Timestamp `json:"started"`
+	Finished Timestamp `json:"finished"`
+}
+
+// ReviewInput is posted to a revision to add a message or set labels on it.
+type ReviewInput struct {
+	Message string            `json:"message,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Tag     string            `json:"tag,omitempty"`
+}
+
+type RevertInput struct {
+	Message string `json:"message,omitempty"`
+}
+
+type CherryPickInput struct {
+	Destination string `json:"destination"`
+	Message     string `json:"message,omitempty"`
+}
+
+type ReviewerInput struct {
+	Reviewer string `json:"reviewer"`
+}
+
+type HashtagsInput struct {
+	Add    []string `json:"add,omitempty"`
+	Remove []string `json:"remove,omitempty"`
+}
+
+// PipelineResult is reported back to the connector by a pipeline once it has finished.
+type PipelineResult struct {
+	ChangeNumber   string `json:"changeNumber"`
+	PatchSetNumber string `json:"patchSetNumber"`
+	Status         string `json:"status"`
+	Message        string `json:"message"`
+	URL            string `json:"url"`
+	// Changes are the changes of the topic merged together with the change, as sent in the merge payload.
+	Changes []PipelineChange `json:"changes"`
+}
+
+// PipelineChange identifies a revision a pipeline result is about.
+type PipelineChange struct {
 	ChangeNumber   string `json:"changeNumber"`
 	PatchSetNumber string `json:"patchSetNumber"`
-	CheckerUUID    string `json:"checkerUUID"`
 }